@@ -0,0 +1,245 @@
+package arc
+
+import (
+	"container/list"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+)
+
+// Arc Adaptive replacement cache policy
+type Arc[K comparable, V any] struct {
+	capacity     int
+	target       int
+	elementMap   map[K]*list.Element
+	t1           *list.List
+	t2           *list.List
+	b1           *list.List
+	b2           *list.List
+	evictHandler func(key K, value V)
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	owner *list.List
+}
+
+// New returns new typed arc
+func New[K comparable, V any](capacity int) (*Arc[K, V], error) {
+	if capacity <= 0 {
+		return nil, errs.ErrNonPositiveCapacity
+	}
+	arc := &Arc[K, V]{
+		capacity:   capacity,
+		target:     0,
+		elementMap: make(map[K]*list.Element),
+		t1:         list.New(),
+		t2:         list.New(),
+		b1:         list.New(),
+		b2:         list.New(),
+	}
+	return arc, nil
+}
+
+// NewArc returns new arc
+func NewArc(capacity int) (*Arc[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity)
+}
+
+// Add adds entry in cache
+func (a *Arc[K, V]) Add(key K, value V) (eviction bool) {
+	if element, ok := a.elementMap[key]; ok {
+		entry := element.Value.(*entry[K, V])
+		switch entry.owner {
+		case a.t1, a.t2:
+			entry.value = value
+			a.move(element, a.t2)
+			return false
+		case a.b1:
+			delta := 1
+			if a.b2.Len() > a.b1.Len() {
+				delta = a.b2.Len() / a.b1.Len()
+			}
+			a.target = min(a.target+delta, a.capacity)
+			if a.t1.Len()+a.t2.Len() >= a.capacity {
+				eviction = a.replace(false)
+			}
+		case a.b2:
+			delta := 1
+			if a.b1.Len() > a.b2.Len() {
+				delta = a.b1.Len() / a.b2.Len()
+			}
+			a.target = max(a.target-delta, 0)
+			if a.t1.Len()+a.t2.Len() >= a.capacity {
+				eviction = a.replace(true)
+			}
+		}
+		entry.value = value
+		a.move(element, a.t2)
+		return
+	}
+	if a.t1.Len()+a.t2.Len() >= a.capacity {
+		eviction = a.replace(false)
+	}
+	if a.b1.Len() > a.capacity-a.target {
+		a.removeOldest(a.b1)
+	}
+	if a.b2.Len() > a.target {
+		a.removeOldest(a.b2)
+	}
+	entry := &entry[K, V]{
+		key:   key,
+		value: value,
+		owner: a.t1,
+	}
+	element := a.t1.PushFront(entry)
+	a.elementMap[key] = element
+	return
+}
+
+// Get returns value of cached entry.
+func (a *Arc[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if element, ok := a.elementMap[key]; ok {
+		entry := element.Value.(*entry[K, V])
+		if entry.owner != a.t1 && entry.owner != a.t2 {
+			return value, false
+		}
+		if trigger {
+			a.move(element, a.t2)
+		}
+		return entry.value, true
+	}
+	return
+}
+
+// replace moves the least recently used resident entry into its ghost list.
+// The entry is taken from t1 when t1 exceeds the adaptive target.
+func (a *Arc[K, V]) replace(inB2 bool) bool {
+	t1Len := a.t1.Len()
+	if t1Len > 0 && (t1Len > a.target || (t1Len == a.target && inB2)) {
+		if element := a.t1.Back(); element != nil {
+			a.move(element, a.b1)
+			return true
+		}
+	}
+	if element := a.t2.Back(); element != nil {
+		a.move(element, a.b2)
+		return true
+	}
+	if element := a.t1.Back(); element != nil {
+		a.move(element, a.b1)
+		return true
+	}
+	return false
+}
+
+// OnEvict sets handler called with entries evicted due to capacity.
+// Entries are evicted when they are moved into the ghost lists.
+func (a *Arc[K, V]) OnEvict(handler func(key K, value V)) {
+	a.evictHandler = handler
+}
+
+// move pushes the element to the front of the given list.
+// Values of entries moved into the ghost lists are released.
+func (a *Arc[K, V]) move(element *list.Element, to *list.List) {
+	entry := element.Value.(*entry[K, V])
+	if entry.owner == to {
+		to.MoveToFront(element)
+		return
+	}
+	entry.owner.Remove(element)
+	entry.owner = to
+	if to == a.b1 || to == a.b2 {
+		if a.evictHandler != nil {
+			a.evictHandler(entry.key, entry.value)
+		}
+		var zero V
+		entry.value = zero
+	}
+	a.elementMap[entry.key] = to.PushFront(entry)
+}
+
+func (a *Arc[K, V]) removeOldest(l *list.List) {
+	element := l.Back()
+	if element != nil {
+		delete(a.elementMap, element.Value.(*entry[K, V]).key)
+		l.Remove(element)
+	}
+}
+
+// Remove removes cache entry.
+func (a *Arc[K, V]) Remove(key K) bool {
+	element, ok := a.elementMap[key]
+	if !ok {
+		return false
+	}
+	entry := element.Value.(*entry[K, V])
+	delete(a.elementMap, key)
+	entry.owner.Remove(element)
+	return entry.owner == a.t1 || entry.owner == a.t2
+}
+
+// Clear removes all entries in the cache.
+func (a *Arc[K, V]) Clear() int {
+	length := a.Len()
+	for key := range a.elementMap {
+		delete(a.elementMap, key)
+	}
+	a.t1.Init()
+	a.t2.Init()
+	a.b1.Init()
+	a.b2.Init()
+	a.target = 0
+	return length
+}
+
+// Len returns length of the cache.
+func (a *Arc[K, V]) Len() int {
+	return a.t1.Len() + a.t2.Len()
+}
+
+// Cap returns capacity of the cache.
+func (a *Arc[K, V]) Cap() int {
+	return a.capacity
+}
+
+// SetCap set capacity of the cache.
+// Returns error unless newCap is negative value.
+func (a *Arc[K, V]) SetCap(newCapacity int) error {
+	if newCapacity <= 0 {
+		return errs.ErrNonPositiveCapacity
+	}
+	a.capacity = newCapacity
+	a.target = min(a.target, newCapacity)
+	for a.Len() > newCapacity {
+		a.replace(false)
+	}
+	for a.t1.Len()+a.b1.Len() > newCapacity && a.b1.Len() > 0 {
+		a.removeOldest(a.b1)
+	}
+	for a.Len()+a.b1.Len()+a.b2.Len() > 2*newCapacity && a.b2.Len() > 0 {
+		a.removeOldest(a.b2)
+	}
+	return nil
+}
+
+// Keys returns a slice of entry keys in the cache.
+func (a *Arc[K, V]) Keys() []K {
+	keys := make([]K, 0, a.Len())
+	for k, v := range a.elementMap {
+		if owner := v.Value.(*entry[K, V]).owner; owner == a.t1 || owner == a.t2 {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Values returns a slice of entry values in the cache.
+func (a *Arc[K, V]) Values() []V {
+	values := make([]V, 0, a.Len())
+	for _, v := range a.elementMap {
+		if entry := v.Value.(*entry[K, V]); entry.owner == a.t1 || entry.owner == a.t2 {
+			values = append(values, entry.value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,369 @@
+package arc
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNewArc(t *testing.T) {
+	arc, err := NewArc(1)
+	if arc == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	arc, err = NewArc(0)
+	if arc != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	arc, err = NewArc(-1)
+	if arc != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNew(t *testing.T) {
+	arc, err := New[string, int](1)
+	if arc == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	arc.Add("1", 1)
+	if value, ok := arc.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	arc, err = New[string, int](0)
+	if arc != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestArc_Add(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity*2; i++ {
+		arc.Add(i, strconv.Itoa(i))
+	}
+}
+
+func TestArc_Add2(t *testing.T) {
+	capacity := 3
+	arc, _ := NewArc(capacity)
+	// 1 -> []
+	arc.Add(1, nil)
+	// [1]
+	if !contains(arc.Keys(), 1) {
+		t.FailNow()
+	}
+	// 2 -> [1]
+	arc.Add(2, nil)
+	// [1,2]
+	if !containsAll(arc.Keys(), 1, 2) {
+		t.FailNow()
+	}
+	// 3 -> [1,2]
+	arc.Add(3, nil)
+	// [1,2,3]
+	if !containsAll(arc.Keys(), 1, 2, 3) {
+		t.FailNow()
+	}
+	// 4 -> [1,2,3]
+	arc.Add(4, nil)
+	// [2,3,4]
+	if !containsAll(arc.Keys(), 2, 3, 4) {
+		t.FailNow()
+	}
+	// 2 -> [2,3,4]
+	arc.Add(2, nil)
+	// [3,4,2]
+	if !containsAll(arc.Keys(), 2, 3, 4) {
+		t.FailNow()
+	}
+	// 5 -> [3,4,2]
+	arc.Add(5, nil)
+	// [4,2,5]
+	if !containsAll(arc.Keys(), 2, 4, 5) {
+		t.FailNow()
+	}
+}
+
+func TestArc_Get(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity; i++ {
+		arc.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		value, ok := arc.Get(i, true)
+		if !ok || value.(string) != strconv.Itoa(i) {
+			t.FailNow()
+		}
+	}
+	for i := capacity; i < capacity*2; i++ {
+		value, ok := arc.Get(i, true)
+		if ok || value != nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestArc_Remove(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity; i++ {
+		arc.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		ok := arc.Remove(i)
+		if !ok {
+			t.FailNow()
+		}
+	}
+	for i := 0; i < capacity; i++ {
+		ok := arc.Remove(i)
+		if ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestArc_Clear(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity; i++ {
+		arc.Add(i, strconv.Itoa(i))
+	}
+	arc.Clear()
+	if arc.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestArc_Cap(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	if arc.Cap() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestArc_Len(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	if arc.Len() != 0 {
+		t.FailNow()
+	}
+	for i := 0; i < capacity*2; i++ {
+		arc.Add(i, strconv.Itoa(i))
+		if arc.Len() != int(math.Min(float64(i+1), float64(capacity))) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestArc_SetCap(t *testing.T) {
+	capacity := 10
+	newCapacity := 20
+	arc, _ := NewArc(capacity)
+	err := arc.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if arc.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = 5
+	err = arc.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if arc.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = -1
+	err = arc.SetCap(newCapacity)
+	if err == nil {
+		t.FailNow()
+	}
+	if arc.Cap() == newCapacity {
+		t.FailNow()
+	}
+}
+
+func TestArc_Keys(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity*2; i++ {
+		arc.Add(i, strconv.Itoa(i))
+		keys := arc.Keys()
+		if !contains(keys, i) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestArc_Values(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity*2; i++ {
+		arc.Add(i, strconv.Itoa(i))
+		values := arc.Values()
+		if !contains(values, strconv.Itoa(i)) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestArc_Get2(t *testing.T) {
+	capacity := 3
+	arc, _ := NewArc(capacity)
+	arc.Add(1, nil)
+	arc.Add(2, nil)
+	arc.Add(3, nil)
+	// peek must not promote 1 to t2
+	arc.Get(1, false)
+	if arc.t2.Len() != 0 {
+		t.FailNow()
+	}
+	arc.Get(1, true)
+	if arc.t2.Len() != 1 {
+		t.FailNow()
+	}
+	// 4 -> t1:[3,2] t2:[1]
+	arc.Add(4, nil)
+	// t1:[4,3] t2:[1] b1:[2]
+	if !containsAll(arc.Keys(), 1, 3, 4) {
+		t.FailNow()
+	}
+	if _, ok := arc.Get(2, true); ok {
+		t.FailNow()
+	}
+}
+
+func TestArc_Add3(t *testing.T) {
+	capacity := 4
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity; i++ {
+		arc.Add(i, nil)
+	}
+	// 4 -> t1:[3,2,1,0]
+	arc.Add(4, nil)
+	// t1:[4,3,2,1] b1:[0]
+	if arc.target != 0 {
+		t.FailNow()
+	}
+	// ghost hit on 0 grows the target of t1
+	arc.Add(0, nil)
+	if arc.target != 1 {
+		t.FailNow()
+	}
+	if !contains(arc.Keys(), 0) || arc.Len() != capacity {
+		t.FailNow()
+	}
+	if arc.t2.Front().Value.(*entry[interface{}, interface{}]).key != 0 {
+		t.FailNow()
+	}
+}
+
+func TestArc_Scan(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity/2; i++ {
+		arc.Add(i, nil)
+		arc.Get(i, true)
+	}
+	for i := capacity; i < capacity*10; i++ {
+		arc.Add(i, nil)
+	}
+	for i := 0; i < capacity/2; i++ {
+		if _, ok := arc.Get(i, false); !ok {
+			t.FailNow()
+		}
+	}
+	if arc.Len() != capacity {
+		t.FailNow()
+	}
+	if len(arc.elementMap) > capacity*2 {
+		t.FailNow()
+	}
+}
+
+func TestArc_SetCap2(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	for i := 0; i < capacity*3; i++ {
+		arc.Add(i, nil)
+		arc.Get(i-1, true)
+	}
+	newCapacity := 3
+	arc.SetCap(newCapacity)
+	if arc.Len() != newCapacity {
+		t.FailNow()
+	}
+	if len(arc.elementMap) > newCapacity*2 {
+		t.FailNow()
+	}
+}
+
+func contains(s []interface{}, e interface{}) bool {
+	for _, c := range s {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(s []interface{}, es ...interface{}) bool {
+	for _, e := range es {
+		if !contains(s, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestArc_OnEvict(t *testing.T) {
+	capacity := 10
+	arc, _ := NewArc(capacity)
+	evicted := make([]interface{}, 0)
+	arc.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		arc.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	arc.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || arc.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != 0 {
+		t.FailNow()
+	}
+	if _, ok := arc.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	arc.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || arc.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,249 @@
+package nucleus
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// BatchLoaderFunc loads values of the keys missing in the cache.
+// Keys which are not found are left out of the returned map, their loads
+// fail with ErrNotFound.
+type BatchLoaderFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// GetMany returns values of the cached entries among the keys.
+// Entries are accessed as in Get, under a single lock.
+func (c *Cache[K, V]) GetMany(keys []K) map[K]V {
+	read := c.getLock()
+	defer c.getUnlock(read)
+	values := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, ok := c.get(key); ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// AddMany adds the entries in cache under a single lock.
+// Entries are added in no particular order, so entries evicted due to
+// capacity may be any of them.
+// Returns true if any entry is evicted.
+func (c *Cache[K, V]) AddMany(entries map[K]V) (eviction bool) {
+	c.lock.Lock()
+	defer c.unlock()
+	for key, value := range entries {
+		old, exists := c.policy.Get(key, false)
+		if c.policy.Add(key, value) {
+			eviction = true
+		}
+		c.recordPut(key, old, exists)
+	}
+	return
+}
+
+// RemoveMany removes the entries of the keys under a single lock.
+// Returns count of removed entries.
+func (c *Cache[K, V]) RemoveMany(keys []K) (count int) {
+	c.lock.Lock()
+	defer c.unlock()
+	for _, key := range keys {
+		value, found := c.policy.Get(key, false)
+		if c.policy.Remove(key) {
+			count++
+		}
+		if found {
+			c.recordEviction(key, value, EvictRemoved)
+		}
+	}
+	c.loadGroup.forget(keys...)
+	return
+}
+
+// GetOrLoadMany returns values of the cached entries among the keys, and
+// loads the missing ones by a single loader call and adds them in the cache.
+// Keys being loaded by GetOrLoad or another GetOrLoadMany are waited for
+// instead of loaded again, keys whose loads failed within the negative ttl
+// are not loaded again.
+// Returns values of the keys which are cached or loaded, and the first
+// error among the loader, the failed loads and the context.
+func (c *Cache[K, V]) GetOrLoadMany(ctx context.Context, keys []K, loader BatchLoaderFunc[K, V]) (values map[K]V, err error) {
+	values = c.GetMany(keys)
+	g := &c.loadGroup
+	now := g.now()
+	missing := make([]K, 0, len(keys)-len(values))
+	waits := make(map[K]*loadCall[V])
+	calls := make(map[K]*loadCall[V])
+	g.lock.Lock()
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		if _, ok := calls[key]; ok {
+			continue
+		}
+		if _, ok := waits[key]; ok {
+			continue
+		}
+		if failure, ok := g.failures[key]; ok {
+			if now.Before(failure.expiresAt) {
+				if err == nil {
+					err = failure.err
+				}
+				continue
+			}
+			delete(g.failures, key)
+		}
+		if call, ok := g.calls[key]; ok {
+			waits[key] = call
+			continue
+		}
+		if g.calls == nil {
+			g.calls = make(map[K]*loadCall[V])
+		}
+		call := &loadCall[V]{done: make(chan struct{}), err: &LoadError{Key: key, Err: ErrLoaderPanicked}}
+		g.calls[key] = call
+		calls[key] = call
+		missing = append(missing, key)
+	}
+	g.lock.Unlock()
+
+	if len(missing) > 0 {
+		if loadErr := c.loadMany(ctx, missing, calls, loader); loadErr != nil && err == nil {
+			err = loadErr
+		}
+		for key, call := range calls {
+			if call.err == nil {
+				values[key] = call.value
+			}
+		}
+	}
+	for key, call := range waits {
+		select {
+		case <-call.done:
+			if call.err == nil {
+				values[key] = call.value
+			} else if err == nil && !errors.Is(call.err, ErrNotFound) {
+				err = call.err
+			}
+		case <-ctx.Done():
+			if err == nil {
+				err = ctx.Err()
+			}
+			return
+		}
+	}
+	return
+}
+
+// loadMany loads the keys by loader, and completes their calls registered
+// in the load group.
+// Returns error of the first key if the loader fails.
+func (c *Cache[K, V]) loadMany(ctx context.Context, keys []K, calls map[K]*loadCall[V], loader BatchLoaderFunc[K, V]) (err error) {
+	g := &c.loadGroup
+	err = ErrLoaderPanicked
+	defer func() {
+		g.lock.Lock()
+		for _, key := range keys {
+			delete(g.calls, key)
+			if err != nil && g.negativeTTL > 0 {
+				g.addFailure(key, calls[key].err)
+			}
+		}
+		g.lock.Unlock()
+		for _, call := range calls {
+			close(call.done)
+		}
+	}()
+	start := time.Now()
+	loaded, err := loader(ctx, keys)
+	c.stats.recordLoad(err, time.Since(start))
+	entries := make(map[K]V, len(keys))
+	for _, key := range keys {
+		call := calls[key]
+		if err != nil {
+			call.err = &LoadError{Key: key, Err: err}
+			continue
+		}
+		value, ok := loaded[key]
+		if !ok {
+			call.err = &LoadError{Key: key, Err: ErrNotFound}
+			continue
+		}
+		call.value, call.err = value, nil
+		entries[key] = value
+	}
+	if len(entries) > 0 {
+		c.AddMany(entries)
+	}
+	if err != nil {
+		return calls[keys[0]].err
+	}
+	return nil
+}
+
+// GetMany returns values of the cached entries among the keys, locking
+// every shard once.
+func (s *ShardedCache[K, V]) GetMany(keys []K) map[K]V {
+	values := make(map[K]V, len(keys))
+	for shard, shardKeys := range s.group(keys) {
+		for key, value := range shard.GetMany(shardKeys) {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// AddMany adds the entries in their shards, locking every shard once.
+// Returns true if any entry is evicted.
+func (s *ShardedCache[K, V]) AddMany(entries map[K]V) (eviction bool) {
+	shardEntries := make(map[*Cache[K, V]]map[K]V)
+	for key, value := range entries {
+		shard := s.shard(key)
+		if shardEntries[shard] == nil {
+			shardEntries[shard] = make(map[K]V)
+		}
+		shardEntries[shard][key] = value
+	}
+	for shard, entries := range shardEntries {
+		if shard.AddMany(entries) {
+			eviction = true
+		}
+	}
+	return
+}
+
+// RemoveMany removes the entries of the keys, locking every shard once.
+// Returns count of removed entries.
+func (s *ShardedCache[K, V]) RemoveMany(keys []K) (count int) {
+	for shard, shardKeys := range s.group(keys) {
+		count += shard.RemoveMany(shardKeys)
+	}
+	return
+}
+
+// GetOrLoadMany returns values of the cached entries among the keys, and
+// loads the missing ones by a loader call per shard.
+func (s *ShardedCache[K, V]) GetOrLoadMany(ctx context.Context, keys []K, loader BatchLoaderFunc[K, V]) (values map[K]V, err error) {
+	values = make(map[K]V, len(keys))
+	for shard, shardKeys := range s.group(keys) {
+		shardValues, shardErr := shard.GetOrLoadMany(ctx, shardKeys, loader)
+		for key, value := range shardValues {
+			values[key] = value
+		}
+		if shardErr != nil && err == nil {
+			err = shardErr
+		}
+	}
+	return
+}
+
+// group groups the keys by their shards.
+func (s *ShardedCache[K, V]) group(keys []K) map[*Cache[K, V]][]K {
+	groups := make(map[*Cache[K, V]][]K)
+	for _, key := range keys {
+		shard := s.shard(key)
+		groups[shard] = append(groups[shard], key)
+	}
+	return groups
+}
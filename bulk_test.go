@@ -0,0 +1,235 @@
+package nucleus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_GetMany(t *testing.T) {
+	capacity := 3
+	cache, _ := NewLruCache(capacity)
+	cache.AddMany(map[interface{}]interface{}{1: "1", 2: "2", 3: "3"})
+	if cache.Len() != capacity || cache.Stats().Adds != 3 {
+		t.FailNow()
+	}
+	values := cache.GetMany([]interface{}{1, 4})
+	if len(values) != 1 || values[1] != "1" {
+		t.FailNow()
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.FailNow()
+	}
+	// accessed entries are moved to the front
+	cache.Add(4, "4")
+	if !cache.Contains(1) || cache.Len() != capacity {
+		t.FailNow()
+	}
+	evictions := recordEvictions(cache)
+	if cache.RemoveMany([]interface{}{1, 2, 3}) != 2 || cache.Len() != 1 {
+		t.FailNow()
+	}
+	if e := evictions(); len(e) != 2 || e[0].reason != EvictRemoved || e[1].reason != EvictRemoved {
+		t.FailNow()
+	}
+	if !cache.AddMany(map[interface{}]interface{}{5: "5", 6: "6", 7: "7"}) {
+		t.FailNow()
+	}
+}
+
+func TestCache_GetOrLoadMany(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLruCache(capacity)
+	cache.Add(1, 10)
+	calls := int32(0)
+	loader := func(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		values := make(map[interface{}]interface{})
+		for _, key := range keys {
+			if key != 4 {
+				values[key] = key.(int) * 10
+			}
+		}
+		return values, nil
+	}
+	values, err := cache.GetOrLoadMany(context.Background(), []interface{}{1, 2, 3, 3, 4}, loader)
+	if err != nil || len(values) != 3 || values[2] != 20 || values[3] != 30 {
+		t.FailNow()
+	}
+	if calls != 1 || !cache.Contains(2) || cache.Contains(4) {
+		t.FailNow()
+	}
+	if stats := cache.Stats(); stats.LoadSuccesses != 1 || stats.Adds != 3 {
+		t.FailNow()
+	}
+	values, _ = cache.GetOrLoadMany(context.Background(), []interface{}{1, 2, 3}, loader)
+	if len(values) != 3 || calls != 1 {
+		t.FailNow()
+	}
+}
+
+func TestCache_GetOrLoadMany2(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLruCache(capacity)
+	_ = cache.SetNegativeTTL(time.Hour)
+	loadErr := errors.New("load failed")
+	calls := int32(0)
+	loader := func(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, loadErr
+	}
+	cache.Add(1, 1)
+	values, err := cache.GetOrLoadMany(context.Background(), []interface{}{1, 2}, loader)
+	if !errors.Is(err, loadErr) || len(values) != 1 {
+		t.FailNow()
+	}
+	values, err = cache.GetOrLoadMany(context.Background(), []interface{}{1, 2}, loader)
+	if !errors.Is(err, loadErr) || len(values) != 1 || calls != 1 {
+		t.FailNow()
+	}
+	if _, err := cache.GetOrLoad(context.Background(), 2, func(ctx context.Context, key interface{}) (interface{}, error) {
+		return key, nil
+	}); !errors.Is(err, loadErr) {
+		t.FailNow()
+	}
+}
+
+func TestCache_GetOrLoadMany3(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLruCache(capacity)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _ = cache.GetOrLoad(context.Background(), 1, func(ctx context.Context, key interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return "single", nil
+		})
+	}()
+	<-started
+	done := make(chan map[interface{}]interface{})
+	go func() {
+		values, _ := cache.GetOrLoadMany(context.Background(), []interface{}{1, 2}, func(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+			if len(keys) != 1 || keys[0] != 2 {
+				t.Error("unexpected keys")
+			}
+			return map[interface{}]interface{}{2: "batch"}, nil
+		})
+		done <- values
+	}()
+	close(release)
+	if values := <-done; len(values) != 2 || values[1] != "single" || values[2] != "batch" {
+		t.FailNow()
+	}
+}
+
+func TestShardedCache_GetMany(t *testing.T) {
+	capacity := 64
+	sharded, _ := NewShardedCache(4, capacity, NewLruCache)
+	entries := make(map[interface{}]interface{})
+	keys := make([]interface{}, 0)
+	for i := 0; i < 8; i++ {
+		entries[i] = i
+		keys = append(keys, i)
+	}
+	sharded.AddMany(entries)
+	if len(sharded.GetMany(keys)) != 8 {
+		t.FailNow()
+	}
+	if sharded.RemoveMany(keys[:4]) != 4 || sharded.Len() != 4 {
+		t.FailNow()
+	}
+	values, err := sharded.GetOrLoadMany(context.Background(), keys, func(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+		values := make(map[interface{}]interface{})
+		for _, key := range keys {
+			values[key] = key
+		}
+		return values, nil
+	})
+	if err != nil || len(values) != 8 || sharded.Len() != 8 {
+		t.FailNow()
+	}
+}
+
+const benchmarkBatch = 100
+
+func BenchmarkCache_GetMany(b *testing.B) {
+	capacity := 1 << 12
+	cache, _ := NewLru[int, int](capacity)
+	keys := benchmarkKeys(cache, capacity)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.GetMany(keys[i%len(keys)])
+			i++
+		}
+	})
+}
+
+func BenchmarkCache_GetLoop(b *testing.B) {
+	capacity := 1 << 12
+	cache, _ := NewLru[int, int](capacity)
+	keys := benchmarkKeys(cache, capacity)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			values := make(map[int]int, benchmarkBatch)
+			for _, key := range keys[i%len(keys)] {
+				if value, ok := cache.Get(key); ok {
+					values[key] = value
+				}
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkCache_AddMany(b *testing.B) {
+	capacity := 1 << 12
+	cache, _ := NewLru[int, int](capacity)
+	entries := make(map[int]int, benchmarkBatch)
+	for i := 0; i < benchmarkBatch; i++ {
+		entries[i] = i
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			cache.AddMany(entries)
+		}
+	})
+}
+
+func BenchmarkCache_AddLoop(b *testing.B) {
+	capacity := 1 << 12
+	cache, _ := NewLru[int, int](capacity)
+	entries := make(map[int]int, benchmarkBatch)
+	for i := 0; i < benchmarkBatch; i++ {
+		entries[i] = i
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for key, value := range entries {
+				cache.Add(key, value)
+			}
+		}
+	})
+}
+
+// benchmarkKeys fills the cache and returns batches of its keys.
+func benchmarkKeys(cache *Cache[int, int], capacity int) [][]int {
+	batches := make([][]int, 0, capacity/benchmarkBatch)
+	for i := 0; i+benchmarkBatch <= capacity; i += benchmarkBatch {
+		batch := make([]int, benchmarkBatch)
+		for j := range batch {
+			batch[j] = i + j
+			cache.Add(i+j, i+j)
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
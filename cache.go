@@ -1,164 +1,432 @@
 package nucleus
 
 import (
-	"github.com/SemihBKGR/nucleus/fifo"
-	"github.com/SemihBKGR/nucleus/lru"
-	"github.com/SemihBKGR/nucleus/mru"
-	"github.com/SemihBKGR/nucleus/tlru"
+	"errors"
+	"fmt"
+	"github.com/SemihBKGR/nucleus/slru"
+	"github.com/SemihBKGR/nucleus/twoq"
 	"sync"
 	"time"
 )
 
 // Policy base interface of policies.
-type Policy interface {
-	Add(key, value interface{}) (eviction bool)
-	Get(key interface{}, trigger bool) (value interface{}, ok bool)
-	Remove(key interface{}) (ok bool)
+type Policy[K comparable, V any] interface {
+	Add(key K, value V) (eviction bool)
+	Get(key K, trigger bool) (value V, ok bool)
+	Remove(key K) (ok bool)
 	Clear() int
 	Len() int
 	Cap() int
 	SetCap(int) error
-	Keys() []interface{}
-	Values() []interface{}
+	Keys() []K
+	Values() []V
+}
+
+// ConcurrentPolicy is implemented by policies whose Get only updates atomic
+// state, so that Cache.Get can run under the read lock.
+type ConcurrentPolicy interface {
+	ConcurrentGet() bool
+}
+
+// ExpirablePolicy is implemented by policies whose entries expire.
+// Reads remove expired entries from such policies, so the cache runs
+// them under the write lock.
+type ExpirablePolicy[K comparable, V any] interface {
+	AddWithTTL(key K, value V, ttl time.Duration) (eviction bool)
+	Expire() int
+	OnExpire(handler func(key K, value V))
+}
+
+// DaemonPolicy is implemented by policies running a background goroutine
+// which is stopped by Cache.Close.
+type DaemonPolicy interface {
+	StopDaemon() bool
+}
+
+// OrderedPolicy is implemented by policies keeping entries in a list,
+// walking them from the least recently added or used one to the most
+// recent one.
+type OrderedPolicy[K comparable, V any] interface {
+	Range(fn func(key K, value V) bool)
+	Oldest() (key K, value V, ok bool)
+	Newest() (key K, value V, ok bool)
 }
 
 // Cache is main struct.
-type Cache struct {
-	policy Policy
-	lock   sync.RWMutex
+type Cache[K comparable, V any] struct {
+	policy      Policy[K, V]
+	lock        sync.RWMutex
+	closeOnce   sync.Once
+	loadGroup   loadGroup[K, V]
+	onEvict     EvictionCallback[K, V]
+	evictReason EvictReason
+	evictions   []eviction[K, V]
+	stats       statsCounter
+	codec       Codec
+	refresher   *refresher[K, V]
+	closed      bool
 }
 
-// NewLruCache returns new cache with lru policy.
-func NewLruCache(cap int) (*Cache, error) {
-	lruPolicy, err := lru.NewLru(cap)
-	if err != nil {
-		return nil, err
+func newCache[K comparable, V any](policy Policy[K, V]) *Cache[K, V] {
+	cache := &Cache[K, V]{
+		policy: policy,
 	}
-	cache := &Cache{
-		policy: lruPolicy,
+	cache.hookPolicy()
+	return cache
+}
+
+// newCompat creates cache by New for the constructors taking the settings as
+// arguments, which return the cause of *OptionError since their callers do
+// not pass the options it names.
+func newCompat[K comparable, V any](opts ...Option) (*Cache[K, V], error) {
+	cache, err := New[K, V](opts...)
+	var optionErr *OptionError
+	if errors.As(err, &optionErr) && optionErr.Err != nil {
+		return nil, optionErr.Err
 	}
-	return cache, nil
+	return cache, err
 }
 
-// NewMruCache returns new cache with mru policy.
-func NewMruCache(cap int) (*Cache, error) {
-	mruPolicy, err := mru.NewMru(cap)
+// NewLru returns new typed cache with lru policy.
+func NewLru[K comparable, V any](cap int) (*Cache[K, V], error) {
+	return newCompat[K, V](WithPolicy(LruPolicy), WithCapacity(cap), WithStats())
+}
+
+// NewMru returns new typed cache with mru policy.
+func NewMru[K comparable, V any](cap int) (*Cache[K, V], error) {
+	return newCompat[K, V](WithPolicy(MruPolicy), WithCapacity(cap), WithStats())
+}
+
+// NewFifo returns new typed cache with fifo policy.
+func NewFifo[K comparable, V any](cap int) (*Cache[K, V], error) {
+	return newCompat[K, V](WithPolicy(FifoPolicy), WithCapacity(cap), WithStats())
+}
+
+// NewTlru returns new typed cache with tlru policy.
+// Expired entries are swept every expDur until the cache is closed, so it
+// must be closed if expDur is positive value.
+// Entries never expire unless expDur is positive value.
+// Options are applied after the defaults, e.g. WithClock.
+func NewTlru[K comparable, V any](cap int, expDur time.Duration, opts ...Option) (*Cache[K, V], error) {
+	return NewTlruWithSweep[K, V](cap, expDur, expDur, opts...)
+}
+
+// NewTlruWithSweep returns new typed cache with tlru policy whose expired
+// entries are swept every sweepInterval until the cache is closed.
+// The daemon is not started unless sweepInterval is positive value, it must
+// be stopped by Close otherwise.
+// Options are applied after the defaults, e.g. WithClock.
+func NewTlruWithSweep[K comparable, V any](cap int, expDur, sweepInterval time.Duration, opts ...Option) (*Cache[K, V], error) {
+	return newCompat[K, V](append([]Option{
+		WithPolicy(TlruPolicy), WithCapacity(cap), WithTTL(max(expDur, 0)), WithSweepInterval(max(sweepInterval, 0)), WithStats(),
+	}, opts...)...)
+}
+
+// NewLfu returns new typed cache with lfu policy.
+func NewLfu[K comparable, V any](cap int) (*Cache[K, V], error) {
+	return newCompat[K, V](WithPolicy(LfuPolicy), WithCapacity(cap), WithStats())
+}
+
+// NewArc returns new typed cache with arc policy.
+func NewArc[K comparable, V any](cap int) (*Cache[K, V], error) {
+	return newCompat[K, V](WithPolicy(ArcPolicy), WithCapacity(cap), WithStats())
+}
+
+// NewTinyLfu returns new typed cache with window tinylfu policy.
+func NewTinyLfu[K comparable, V any](cap int) (*Cache[K, V], error) {
+	return newCompat[K, V](WithPolicy(TinyLfuPolicy), WithCapacity(cap), WithStats())
+}
+
+// NewTwoQ returns new typed cache with 2q policy.
+func NewTwoQ[K comparable, V any](cap int, recentRatio, ghostRatio float64) (*Cache[K, V], error) {
+	twoQPolicy, err := twoq.New[K, V](cap, recentRatio, ghostRatio)
 	if err != nil {
 		return nil, err
 	}
-	cache := &Cache{
-		policy: mruPolicy,
-	}
-	return cache, nil
+	return NewWithPolicy[K, V](twoQPolicy, WithStats())
 }
 
-// NewFifoCache returns new cache with fifo policy.
-func NewFifoCache(cap int) (*Cache, error) {
-	fifoPolicy, err := fifo.NewFifo(cap)
+// NewSlru returns new typed cache with segmented lru policy.
+func NewSlru[K comparable, V any](cap int, protectedRatio float64) (*Cache[K, V], error) {
+	slruPolicy, err := slru.New[K, V](cap, protectedRatio)
 	if err != nil {
 		return nil, err
 	}
-	cache := &Cache{
-		policy: fifoPolicy,
-	}
-	return cache, nil
+	return NewWithPolicy[K, V](slruPolicy, WithStats())
+}
+
+// NewClock returns new typed cache with clock policy.
+func NewClock[K comparable, V any](cap int) (*Cache[K, V], error) {
+	return newCompat[K, V](WithPolicy(ClockPolicy), WithCapacity(cap), WithStats())
+}
+
+// NewClockPro returns new typed cache with clockpro policy.
+func NewClockPro[K comparable, V any](cap int) (*Cache[K, V], error) {
+	return newCompat[K, V](WithPolicy(ClockProPolicy), WithCapacity(cap), WithStats())
+}
+
+// NewLruCache returns new cache with lru policy.
+func NewLruCache(cap int) (*Cache[interface{}, interface{}], error) {
+	return NewLru[interface{}, interface{}](cap)
+}
+
+// NewMruCache returns new cache with mru policy.
+func NewMruCache(cap int) (*Cache[interface{}, interface{}], error) {
+	return NewMru[interface{}, interface{}](cap)
+}
+
+// NewFifoCache returns new cache with fifo policy.
+func NewFifoCache(cap int) (*Cache[interface{}, interface{}], error) {
+	return NewFifo[interface{}, interface{}](cap)
 }
 
 // NewTlruCache create new cache with tlru policy
-func NewTlruCache(cap int, expDur time.Duration) (*Cache, error) {
-	tlruPolicy, err := tlru.NewTlru(cap, expDur)
-	if err != nil {
-		return nil, err
-	}
-	cache := &Cache{
-		policy: tlruPolicy,
-	}
-	tlruPolicy.StartDaemon(&cache.lock)
-	return cache, nil
+func NewTlruCache(cap int, expDur time.Duration, opts ...Option) (*Cache[interface{}, interface{}], error) {
+	return NewTlru[interface{}, interface{}](cap, expDur, opts...)
+}
+
+// NewTlruCacheWithSweep returns new cache with tlru policy whose expired
+// entries are swept every sweepInterval.
+func NewTlruCacheWithSweep(cap int, expDur, sweepInterval time.Duration, opts ...Option) (*Cache[interface{}, interface{}], error) {
+	return NewTlruWithSweep[interface{}, interface{}](cap, expDur, sweepInterval, opts...)
+}
+
+// NewLfuCache returns new cache with lfu policy.
+func NewLfuCache(cap int) (*Cache[interface{}, interface{}], error) {
+	return NewLfu[interface{}, interface{}](cap)
+}
+
+// NewArcCache returns new cache with arc policy.
+func NewArcCache(cap int) (*Cache[interface{}, interface{}], error) {
+	return NewArc[interface{}, interface{}](cap)
+}
+
+// NewTinyLfuCache returns new cache with window tinylfu policy.
+func NewTinyLfuCache(cap int) (*Cache[interface{}, interface{}], error) {
+	return NewTinyLfu[interface{}, interface{}](cap)
+}
+
+// NewTwoQCache returns new cache with 2q policy.
+func NewTwoQCache(cap int, recentRatio, ghostRatio float64) (*Cache[interface{}, interface{}], error) {
+	return NewTwoQ[interface{}, interface{}](cap, recentRatio, ghostRatio)
+}
+
+// NewSlruCache returns new cache with segmented lru policy.
+func NewSlruCache(cap int, protectedRatio float64) (*Cache[interface{}, interface{}], error) {
+	return NewSlru[interface{}, interface{}](cap, protectedRatio)
+}
+
+// NewClockCache returns new cache with clock policy.
+func NewClockCache(cap int) (*Cache[interface{}, interface{}], error) {
+	return NewClock[interface{}, interface{}](cap)
+}
+
+// NewClockProCache returns new cache with clockpro policy.
+func NewClockProCache(cap int) (*Cache[interface{}, interface{}], error) {
+	return NewClockPro[interface{}, interface{}](cap)
 }
 
 // Add adds entry in cache
-func (c *Cache) Add(key, value interface{}) (eviction bool) {
+func (c *Cache[K, V]) Add(key K, value V) (eviction bool) {
+	c.lock.Lock()
+	defer c.unlock()
+	old, exists := c.policy.Get(key, false)
+	eviction = c.policy.Add(key, value)
+	c.recordPut(key, old, exists)
+	return
+}
+
+// AddWithTTL adds entry in cache which expires after given ttl, zero ttl
+// never expires the entry. The ttl is kept when the entry is updated by Add,
+// Set, Compute or refresh, restarting from the update.
+// Returns error if ttl is negative value or policy is not an ExpirablePolicy.
+func (c *Cache[K, V]) AddWithTTL(key K, value V, ttl time.Duration) (eviction bool, err error) {
+	if ttl < 0 {
+		return false, fmt.Errorf("%w: must not be negative value", ErrInvalidTTL)
+	}
+	policy, ok := c.policy.(ExpirablePolicy[K, V])
+	if !ok {
+		return false, unsupported("ttl")
+	}
 	c.lock.Lock()
-	defer c.lock.Unlock()
-	return c.policy.Add(key, value)
+	defer c.unlock()
+	old, exists := c.policy.Get(key, false)
+	eviction = policy.AddWithTTL(key, value, ttl)
+	c.recordPut(key, old, exists)
+	return eviction, nil
 }
 
 // Set updates cache entry.
 // Returns true if value updated.
-func (c *Cache) Set(key, value interface{}) (ok bool) {
+func (c *Cache[K, V]) Set(key K, value V) (ok bool) {
 	c.lock.Lock()
-	defer c.lock.Unlock()
-	_, ok = c.policy.Get(key, false)
+	defer c.unlock()
+	var old V
+	old, ok = c.policy.Get(key, false)
 	if ok {
 		c.policy.Add(key, value)
+		c.recordPut(key, old, true)
 	}
 	return
 }
 
 // Get returns value of cached entry.
-func (c *Cache) Get(key interface{}) (value interface{}, ok bool) {
+// Get runs under the read lock if the policy is a ConcurrentPolicy.
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	read := c.getLock()
+	defer c.getUnlock(read)
+	return c.get(key)
+}
+
+// getLock locks the cache for Get, for reading if the policy is a
+// ConcurrentPolicy. Returns true if it is locked for reading.
+func (c *Cache[K, V]) getLock() (read bool) {
+	if c.concurrentGet() && !c.expirable() {
+		c.lock.RLock()
+		return true
+	}
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	return false
+}
+
+func (c *Cache[K, V]) getUnlock(read bool) {
+	if read {
+		c.lock.RUnlock()
+	} else {
+		c.unlock()
+	}
+}
+
+// get returns value of the entry and records the access, the cache must be
+// locked by getLock.
+func (c *Cache[K, V]) get(key K) (value V, ok bool) {
 	value, ok = c.policy.Get(key, true)
+	c.stats.recordGet(ok)
+	if ok && c.refresher != nil {
+		c.refresher.check(key)
+	}
 	return
 }
 
 // Remove removes cache entry.
-func (c *Cache) Remove(key interface{}) (ok bool) {
+func (c *Cache[K, V]) Remove(key K) (ok bool) {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer c.unlock()
+	value, found := c.policy.Get(key, false)
 	ok = c.policy.Remove(key)
+	if found {
+		c.recordEviction(key, value, EvictRemoved)
+	}
+	c.loadGroup.forget(key)
 	return
 }
 
 // Contains returns true if there is a cache entry given given key.
-func (c *Cache) Contains(key interface{}) (ok bool) {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+func (c *Cache[K, V]) Contains(key K) (ok bool) {
+	c.readLock()
+	defer c.readUnlock()
 	_, ok = c.policy.Get(key, false)
 	return
 }
 
 // Clear removes all entries in the cache.
-func (c *Cache) Clear() (length int) {
+func (c *Cache[K, V]) Clear() (length int) {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer c.unlock()
+	notify := c.onEvict != nil
+	if notify {
+		for _, key := range c.policy.Keys() {
+			if value, ok := c.policy.Get(key, false); ok {
+				c.recordEviction(key, value, EvictCleared)
+			}
+		}
+	}
 	length = c.policy.Clear()
+	if !notify {
+		c.stats.recordEvictions(EvictCleared, length)
+	}
+	c.loadGroup.forget()
 	return
 }
 
 // Len returns length of the cache.
-func (c *Cache) Len() (len int) {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+func (c *Cache[K, V]) Len() (len int) {
+	c.readLock()
+	defer c.readUnlock()
 	len = c.policy.Len()
 	return
 }
 
 // Cap returns capacity of the cache.
-func (c *Cache) Cap() int {
+func (c *Cache[K, V]) Cap() int {
 	return c.policy.Cap()
 }
 
 // SetCap set capacity of the cache.
 // Returns error unless newCap is negative value.
-func (c *Cache) SetCap(newCap int) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	return c.policy.SetCap(newCap)
+func (c *Cache[K, V]) SetCap(newCap int) error {
+	return c.resize(func() error {
+		return c.policy.SetCap(newCap)
+	})
 }
 
 // Keys returns a slice of entry keys in the cache.
-func (c *Cache) Keys() []interface{} {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+func (c *Cache[K, V]) Keys() []K {
+	c.readLock()
+	defer c.readUnlock()
 	return c.policy.Keys()
 }
 
 // Values returns a slice of entry values in the cache.
-func (c *Cache) Values() []interface{} {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+func (c *Cache[K, V]) Values() []V {
+	c.readLock()
+	defer c.readUnlock()
 	return c.policy.Values()
 }
+
+func (c *Cache[K, V]) concurrentGet() bool {
+	policy, ok := c.policy.(ConcurrentPolicy)
+	return ok && policy.ConcurrentGet()
+}
+
+// Close stops background goroutines of the policy, such as the tlru
+// expiration daemon and refresh workers. The cache remains usable, expired
+// entries are still removed on access, but SetRefresh returns ErrClosed.
+// Calling Close more than once has no effect.
+func (c *Cache[K, V]) Close() error {
+	c.closeOnce.Do(func() {
+		if daemonPolicy, ok := c.policy.(DaemonPolicy); ok {
+			daemonPolicy.StopDaemon()
+		}
+		c.lock.Lock()
+		refresher := c.refresher
+		c.refresher = nil
+		c.closed = true
+		c.lock.Unlock()
+		refresher.stop()
+	})
+	return nil
+}
+
+func (c *Cache[K, V]) expirable() bool {
+	_, ok := c.policy.(ExpirablePolicy[K, V])
+	return ok
+}
+
+// readLock locks the cache for reading, or for writing if reads may remove
+// expired entries from the policy.
+func (c *Cache[K, V]) readLock() {
+	if c.expirable() {
+		c.lock.Lock()
+	} else {
+		c.lock.RLock()
+	}
+}
+
+func (c *Cache[K, V]) readUnlock() {
+	if c.expirable() {
+		c.unlock()
+	} else {
+		c.lock.RUnlock()
+	}
+}
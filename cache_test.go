@@ -1,11 +1,89 @@
 package nucleus
 
 import (
+	"github.com/SemihBKGR/nucleus/slru"
+	"github.com/SemihBKGR/nucleus/tlru"
+	"github.com/SemihBKGR/nucleus/twoq"
 	"math"
+	"runtime"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
+func TestNewLru(t *testing.T) {
+	cache, err := NewLru[string, int](1)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache.Add("1", 1)
+	if value, ok := cache.Get("1"); !ok || value != 1 {
+		t.FailNow()
+	}
+	cache, err = NewLru[string, int](0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewMru(t *testing.T) {
+	cache, err := NewMru[string, int](1)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewMru[string, int](0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewFifo(t *testing.T) {
+	cache, err := NewFifo[string, int](1)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewFifo[string, int](0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewTlru(t *testing.T) {
+	cache, err := NewTlru[string, int](1, 0)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewTlru[string, int](0, 0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
 func TestNewLruCache(t *testing.T) {
 	cache, err := NewLruCache(1)
 	if cache == nil {
@@ -102,6 +180,174 @@ func TestNewTlruCache(t *testing.T) {
 	}
 }
 
+func TestNewLfuCache(t *testing.T) {
+	cache, err := NewLfuCache(1)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewLfuCache(0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	cache, err = NewLfuCache(-1)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewArcCache(t *testing.T) {
+	cache, err := NewArcCache(1)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewArcCache(0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	cache, err = NewArcCache(-1)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewTinyLfuCache(t *testing.T) {
+	cache, err := NewTinyLfuCache(1)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewTinyLfuCache(0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	cache, err = NewTinyLfuCache(-1)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewTwoQCache(t *testing.T) {
+	cache, err := NewTwoQCache(1, twoq.DefaultRecentRatio, twoq.DefaultGhostRatio)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewTwoQCache(0, twoq.DefaultRecentRatio, twoq.DefaultGhostRatio)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	cache, err = NewTwoQCache(1, 0, twoq.DefaultGhostRatio)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewSlruCache(t *testing.T) {
+	cache, err := NewSlruCache(1, slru.DefaultProtectedRatio)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewSlruCache(0, slru.DefaultProtectedRatio)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	cache, err = NewSlruCache(1, 1)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewClockCache(t *testing.T) {
+	cache, err := NewClockCache(1)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewClockCache(0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	cache, err = NewClockCache(-1)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewClockProCache(t *testing.T) {
+	cache, err := NewClockProCache(1)
+	if cache == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	cache, err = NewClockProCache(0)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	cache, err = NewClockProCache(-1)
+	if cache != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
 func TestCache_Add(t *testing.T) {
 	capacity := 10
 	cache, _ := NewLruCache(capacity)
@@ -110,6 +356,35 @@ func TestCache_Add(t *testing.T) {
 	}
 }
 
+func TestCache_AddWithTTL(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCache(capacity, 0)
+	_, err := cache.AddWithTTL(1, "1", 10*time.Millisecond)
+	if err != nil {
+		t.FailNow()
+	}
+	cache.Add(2, "2")
+	if !cache.Contains(1) || !cache.Contains(2) {
+		t.FailNow()
+	}
+	time.Sleep(20 * time.Millisecond)
+	if value, ok := cache.Get(1); ok || value != nil {
+		t.FailNow()
+	}
+	if !cache.Contains(2) {
+		t.FailNow()
+	}
+	_, err = cache.AddWithTTL(3, "3", -time.Second)
+	if err == nil {
+		t.FailNow()
+	}
+	lruCache, _ := NewLruCache(capacity)
+	_, err = lruCache.AddWithTTL(1, "1", time.Second)
+	if err == nil || lruCache.Contains(1) {
+		t.FailNow()
+	}
+}
+
 func TestCache_Set(t *testing.T) {
 	capacity := 10
 	cache, _ := NewLruCache(capacity)
@@ -150,6 +425,38 @@ func TestCache_Get(t *testing.T) {
 	}
 }
 
+func TestCache_Get2(t *testing.T) {
+	capacity := 100
+	cache, _ := NewClockCache(capacity)
+	if !cache.concurrentGet() {
+		t.FailNow()
+	}
+	for i := 0; i < capacity; i++ {
+		cache.Add(i, strconv.Itoa(i))
+	}
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < capacity*2; i++ {
+				value, ok := cache.Get(i % capacity)
+				if ok && value.(string) != strconv.Itoa(i%capacity) {
+					t.Error()
+				}
+			}
+		}()
+	}
+	for i := capacity; i < capacity*2; i++ {
+		cache.Add(i, strconv.Itoa(i))
+	}
+	wg.Wait()
+	lruCache, _ := NewLruCache(capacity)
+	if lruCache.concurrentGet() {
+		t.FailNow()
+	}
+}
+
 func TestCache_Remove(t *testing.T) {
 	capacity := 10
 	cache, _ := NewLruCache(capacity)
@@ -283,3 +590,74 @@ func contains(s []interface{}, e interface{}) bool {
 	}
 	return false
 }
+
+func TestCache_Expire(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCache(capacity, time.Hour)
+	for i := 0; i < 5; i++ {
+		_, _ = cache.AddWithTTL(i, strconv.Itoa(i), 10*time.Millisecond)
+	}
+	cache.Add(5, "5")
+	time.Sleep(20 * time.Millisecond)
+	if cache.Contains(0) {
+		t.FailNow()
+	}
+	if cache.Len() != 1 {
+		t.FailNow()
+	}
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != 5 {
+		t.FailNow()
+	}
+	if values := cache.Values(); len(values) != 1 || values[0] != "5" {
+		t.FailNow()
+	}
+}
+
+func TestNewTlruCacheWithSweep(t *testing.T) {
+	capacity := 10
+	cache, err := NewTlruCacheWithSweep(capacity, time.Hour, 10*time.Millisecond)
+	if cache == nil || err != nil {
+		t.FailNow()
+	}
+	defer cache.Close()
+	_, _ = cache.AddWithTTL(1, "1", 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	cache.lock.Lock()
+	if len(cache.policy.(*tlru.Tlru[interface{}, interface{}]).Keys()) != 0 {
+		t.FailNow()
+	}
+	cache.lock.Unlock()
+	cache, err = NewTlruCacheWithSweep(0, time.Hour, time.Second)
+	if cache != nil || err == nil {
+		t.FailNow()
+	}
+}
+
+func TestCache_Close(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	capacity := 10
+	caches := make([]*Cache[interface{}, interface{}], 0)
+	for i := 0; i < 10; i++ {
+		cache, _ := NewTlruCache(capacity, 10*time.Millisecond)
+		caches = append(caches, cache)
+	}
+	lruCache, _ := NewLruCache(capacity)
+	caches = append(caches, lruCache)
+	for _, cache := range caches {
+		if cache.Close() != nil || cache.Close() != nil {
+			t.FailNow()
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines {
+		if time.Now().After(deadline) {
+			t.FailNow()
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cache := caches[0]
+	cache.Add(1, "1")
+	if value, ok := cache.Get(1); !ok || value != "1" {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,12 @@
+package nucleus
+
+import "time"
+
+// Clock provides current time and timers to the cache, so that expiration
+// of entries can be driven by a fake clock in tests, such as
+// nucleustest.FakeClock. It is used by tlru policy and negative ttl of the
+// loaders.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
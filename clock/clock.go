@@ -0,0 +1,187 @@
+package clock
+
+import (
+	"container/list"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+	"sync/atomic"
+)
+
+// Clock Second chance cache policy.
+// Entries are kept in a circular list swept by a hand. A hit only sets the
+// reference bit of the entry, so Get never modifies the list and can be
+// called concurrently under a read lock.
+type Clock[K comparable, V any] struct {
+	capacity     int
+	elementMap   map[K]*list.Element
+	evictionList *list.List
+	hand         *list.Element
+	evictHandler func(key K, value V)
+}
+
+type entry[K comparable, V any] struct {
+	key        K
+	value      V
+	referenced atomic.Bool
+}
+
+// New returns new typed clock
+func New[K comparable, V any](capacity int) (*Clock[K, V], error) {
+	if capacity <= 0 {
+		return nil, errs.ErrNonPositiveCapacity
+	}
+	clock := &Clock[K, V]{
+		capacity:     capacity,
+		elementMap:   make(map[K]*list.Element),
+		evictionList: list.New(),
+	}
+	return clock, nil
+}
+
+// NewClock returns new clock
+func NewClock(capacity int) (*Clock[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity)
+}
+
+// Add adds entry in cache
+func (c *Clock[K, V]) Add(key K, value V) (eviction bool) {
+	if element, ok := c.elementMap[key]; ok {
+		entry := element.Value.(*entry[K, V])
+		entry.value = value
+		entry.referenced.Store(true)
+		return false
+	}
+	eviction = len(c.elementMap) >= c.capacity
+	if eviction {
+		c.evict()
+	}
+	entry := &entry[K, V]{
+		key:   key,
+		value: value,
+	}
+	var element *list.Element
+	if c.hand == nil {
+		element = c.evictionList.PushBack(entry)
+		c.hand = element
+	} else {
+		element = c.evictionList.InsertBefore(entry, c.hand)
+	}
+	c.elementMap[key] = element
+	return
+}
+
+// Get returns value of cached entry.
+// Get is safe to call concurrently with other Get calls.
+func (c *Clock[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if element, ok := c.elementMap[key]; ok {
+		entry := element.Value.(*entry[K, V])
+		if trigger {
+			entry.referenced.Store(true)
+		}
+		return entry.value, true
+	}
+	return
+}
+
+// ConcurrentGet reports that Get only updates atomic state.
+func (c *Clock[K, V]) ConcurrentGet() bool {
+	return true
+}
+
+// Remove removes cache entry.
+func (c *Clock[K, V]) Remove(key K) bool {
+	element, ok := c.elementMap[key]
+	if !ok {
+		return false
+	}
+	delete(c.elementMap, key)
+	if element == c.hand {
+		c.hand = c.next(element)
+		if c.hand == element {
+			c.hand = nil
+		}
+	}
+	c.evictionList.Remove(element)
+	return true
+}
+
+// OnEvict sets handler called with entries evicted due to capacity.
+func (c *Clock[K, V]) OnEvict(handler func(key K, value V)) {
+	c.evictHandler = handler
+}
+
+// evict sweeps the hand over the entries, giving referenced ones a second
+// chance, and removes the first unreferenced entry.
+func (c *Clock[K, V]) evict() bool {
+	for c.hand != nil {
+		entry := c.hand.Value.(*entry[K, V])
+		if entry.referenced.Swap(false) {
+			c.hand = c.next(c.hand)
+			continue
+		}
+		c.Remove(entry.key)
+		if c.evictHandler != nil {
+			c.evictHandler(entry.key, entry.value)
+		}
+		return true
+	}
+	return false
+}
+
+func (c *Clock[K, V]) next(element *list.Element) *list.Element {
+	if next := element.Next(); next != nil {
+		return next
+	}
+	return c.evictionList.Front()
+}
+
+// Clear removes all entries in the cache.
+func (c *Clock[K, V]) Clear() int {
+	length := c.Len()
+	for key := range c.elementMap {
+		delete(c.elementMap, key)
+	}
+	c.evictionList.Init()
+	c.hand = nil
+	return length
+}
+
+// Len returns length of the cache.
+func (c *Clock[K, V]) Len() int {
+	return c.evictionList.Len()
+}
+
+// Cap returns capacity of the cache.
+func (c *Clock[K, V]) Cap() int {
+	return c.capacity
+}
+
+// SetCap set capacity of the cache.
+// Returns error unless newCap is negative value.
+func (c *Clock[K, V]) SetCap(newCapacity int) error {
+	if newCapacity <= 0 {
+		return errs.ErrNonPositiveCapacity
+	}
+	for c.Len() > newCapacity {
+		c.evict()
+	}
+	c.capacity = newCapacity
+	return nil
+}
+
+// Keys returns a slice of entry keys in the cache.
+func (c *Clock[K, V]) Keys() []K {
+	keys := make([]K, 0, len(c.elementMap))
+	for k := range c.elementMap {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns a slice of entry values in the cache.
+func (c *Clock[K, V]) Values() []V {
+	values := make([]V, 0, len(c.elementMap))
+	for _, v := range c.elementMap {
+		values = append(values, v.Value.(*entry[K, V]).value)
+	}
+	return values
+}
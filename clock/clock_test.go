@@ -0,0 +1,328 @@
+package clock
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestNewClock(t *testing.T) {
+	clock, err := NewClock(1)
+	if clock == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	clock, err = NewClock(0)
+	if clock != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	clock, err = NewClock(-1)
+	if clock != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNew(t *testing.T) {
+	clock, err := New[string, int](1)
+	if clock == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	clock.Add("1", 1)
+	if value, ok := clock.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	clock, err = New[string, int](0)
+	if clock != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestClock_Add(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	for i := 0; i < capacity*2; i++ {
+		clock.Add(i, strconv.Itoa(i))
+	}
+}
+
+func TestClock_Add2(t *testing.T) {
+	capacity := 3
+	clock, _ := NewClock(capacity)
+	// 1 -> []
+	clock.Add(1, nil)
+	// [1]
+	if !contains(clock.Keys(), 1) {
+		t.FailNow()
+	}
+	// 2 -> [1]
+	clock.Add(2, nil)
+	// [1,2]
+	if !containsAll(clock.Keys(), 1, 2) {
+		t.FailNow()
+	}
+	// 3 -> [1,2]
+	clock.Add(3, nil)
+	// [1,2,3]
+	if !containsAll(clock.Keys(), 1, 2, 3) {
+		t.FailNow()
+	}
+	// 4 -> [1,2,3]
+	clock.Add(4, nil)
+	// [2,3,4]
+	if !containsAll(clock.Keys(), 2, 3, 4) {
+		t.FailNow()
+	}
+	// 2 -> [2,3,4]
+	clock.Add(2, nil)
+	// [3,4,2]
+	if !containsAll(clock.Keys(), 2, 3, 4) {
+		t.FailNow()
+	}
+	// 5 -> [3,4,2]
+	clock.Add(5, nil)
+	// [4,2,5]
+	if !containsAll(clock.Keys(), 2, 4, 5) {
+		t.FailNow()
+	}
+}
+
+func TestClock_Get(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	for i := 0; i < capacity; i++ {
+		clock.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		value, ok := clock.Get(i, true)
+		if !ok || value.(string) != strconv.Itoa(i) {
+			t.FailNow()
+		}
+	}
+	for i := capacity; i < capacity*2; i++ {
+		value, ok := clock.Get(i, true)
+		if ok || value != nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClock_Remove(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	for i := 0; i < capacity; i++ {
+		clock.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		ok := clock.Remove(i)
+		if !ok {
+			t.FailNow()
+		}
+	}
+	for i := 0; i < capacity; i++ {
+		ok := clock.Remove(i)
+		if ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClock_Clear(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	for i := 0; i < capacity; i++ {
+		clock.Add(i, strconv.Itoa(i))
+	}
+	clock.Clear()
+	if clock.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestClock_Cap(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	if clock.Cap() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestClock_Len(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	if clock.Len() != 0 {
+		t.FailNow()
+	}
+	for i := 0; i < capacity*2; i++ {
+		clock.Add(i, strconv.Itoa(i))
+		if clock.Len() != int(math.Min(float64(i+1), float64(capacity))) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClock_SetCap(t *testing.T) {
+	capacity := 10
+	newCapacity := 20
+	clock, _ := NewClock(capacity)
+	err := clock.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if clock.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = 5
+	err = clock.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if clock.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = -1
+	err = clock.SetCap(newCapacity)
+	if err == nil {
+		t.FailNow()
+	}
+	if clock.Cap() == newCapacity {
+		t.FailNow()
+	}
+}
+
+func TestClock_Keys(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	for i := 0; i < capacity*2; i++ {
+		clock.Add(i, strconv.Itoa(i))
+		keys := clock.Keys()
+		if !contains(keys, i) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClock_Values(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	for i := 0; i < capacity*2; i++ {
+		clock.Add(i, strconv.Itoa(i))
+		values := clock.Values()
+		if !contains(values, strconv.Itoa(i)) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClock_Get2(t *testing.T) {
+	capacity := 3
+	clock, _ := NewClock(capacity)
+	clock.Add(1, nil)
+	clock.Add(2, nil)
+	clock.Add(3, nil)
+	clock.Get(1, true)
+	clock.Get(2, false)
+	// 4 -> [1*,2,3]
+	clock.Add(4, nil)
+	// [4,1,3]
+	if !containsAll(clock.Keys(), 1, 3, 4) {
+		t.FailNow()
+	}
+}
+
+func TestClock_ConcurrentGet(t *testing.T) {
+	capacity := 100
+	clock, _ := NewClock(capacity)
+	if !clock.ConcurrentGet() {
+		t.FailNow()
+	}
+	for i := 0; i < capacity; i++ {
+		clock.Add(i, strconv.Itoa(i))
+	}
+	lock := sync.RWMutex{}
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < capacity; i++ {
+				lock.RLock()
+				value, ok := clock.Get(i, true)
+				lock.RUnlock()
+				if ok && value.(string) != strconv.Itoa(i) {
+					t.Error()
+				}
+			}
+		}()
+	}
+	for i := capacity; i < capacity*2; i++ {
+		lock.Lock()
+		clock.Add(i, strconv.Itoa(i))
+		lock.Unlock()
+	}
+	wg.Wait()
+}
+
+func contains(s []interface{}, e interface{}) bool {
+	for _, c := range s {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(s []interface{}, es ...interface{}) bool {
+	for _, e := range es {
+		if !contains(s, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestClock_OnEvict(t *testing.T) {
+	capacity := 10
+	clock, _ := NewClock(capacity)
+	evicted := make([]interface{}, 0)
+	clock.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		clock.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	clock.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || clock.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != 0 {
+		t.FailNow()
+	}
+	if _, ok := clock.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	clock.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || clock.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
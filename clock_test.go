@@ -0,0 +1,112 @@
+package nucleus
+
+import (
+	"context"
+	"errors"
+	"github.com/SemihBKGR/nucleus/nucleustest"
+	"testing"
+	"time"
+)
+
+func TestNewTlruCache_WithClock(t *testing.T) {
+	capacity := 10
+	clock := nucleustest.NewFakeClock(time.Now())
+	cache, err := NewTlruCache(capacity, time.Minute, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	evictions := recordEvictions(cache)
+	cache.Add(1, "1")
+	_, _ = cache.AddWithTTL(2, "2", 2*time.Minute)
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	clock.BlockUntil(1)
+	if e := waitEvictions(evictions, 1); len(e) != 1 || e[0] != (evicted{1, "1", EvictExpired}) {
+		t.FailNow()
+	}
+	clock.Advance(30 * time.Second)
+	if value, ok := cache.Get(2); !ok || value != "2" {
+		t.FailNow()
+	}
+	clock.Advance(30 * time.Second)
+	if _, ok := cache.Get(2); ok || cache.Stats().Expirations != 2 {
+		t.FailNow()
+	}
+}
+
+func TestCache_SetNegativeTTL_WithClock(t *testing.T) {
+	capacity := 10
+	clock := nucleustest.NewFakeClock(time.Now())
+	cache, _ := New[int, int](WithCapacity(capacity), WithClock(clock))
+	_ = cache.SetNegativeTTL(time.Minute)
+	calls := 0
+	loader := func(ctx context.Context, key int) (int, error) {
+		calls++
+		return 0, errors.New("load failed")
+	}
+	_, _ = cache.GetOrLoad(context.Background(), 1, loader)
+	clock.Advance(59 * time.Second)
+	_, _ = cache.GetOrLoad(context.Background(), 1, loader)
+	if calls != 1 {
+		t.FailNow()
+	}
+	clock.Advance(time.Second)
+	_, _ = cache.GetOrLoad(context.Background(), 1, loader)
+	if calls != 2 {
+		t.FailNow()
+	}
+	if _, err := New[int, int](WithCapacity(capacity), WithClock(nil)); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestCache_AddWithTTL_WithClock(t *testing.T) {
+	capacity := 10
+	clock := nucleustest.NewFakeClock(time.Now())
+	cache, _ := NewTlru[int, int](capacity, 0, WithClock(clock))
+	defer cache.Close()
+	if _, err := cache.AddWithTTL(1, 1, 500*time.Microsecond); err != nil {
+		t.FailNow()
+	}
+	if value, ok := cache.Get(1); !ok || value != 1 {
+		t.FailNow()
+	}
+	clock.Advance(500 * time.Microsecond)
+	if _, ok := cache.Get(1); ok {
+		t.FailNow()
+	}
+}
+
+func TestCache_AddWithTTL_WithClock2(t *testing.T) {
+	capacity := 10
+	clock := nucleustest.NewFakeClock(time.Now())
+	cache, _ := NewTlru[int, int](capacity, time.Minute, WithClock(clock))
+	defer cache.Close()
+	if _, err := cache.AddWithTTL(1, 1, 0); err != nil {
+		t.FailNow()
+	}
+	_, _ = cache.AddWithTTL(2, 2, time.Hour)
+	_, _ = cache.AddWithTTL(3, 3, time.Hour)
+	clock.Advance(30 * time.Minute)
+	cache.Set(2, 20)
+	cache.Compute(3, func(old int, exists bool) (int, bool) {
+		return old * 10, true
+	})
+	clock.Advance(59 * time.Minute)
+	for key, expected := range map[int]int{1: 1, 2: 20, 3: 30} {
+		if value, ok := cache.Get(key); !ok || value != expected {
+			t.FailNow()
+		}
+	}
+	clock.Advance(time.Minute)
+	if _, ok := cache.Get(1); !ok {
+		t.FailNow()
+	}
+	if _, ok := cache.Get(2); ok {
+		t.FailNow()
+	}
+	if _, ok := cache.Get(3); ok {
+		t.FailNow()
+	}
+}
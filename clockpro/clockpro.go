@@ -0,0 +1,364 @@
+package clockpro
+
+import (
+	"container/list"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+	"sync/atomic"
+)
+
+type status int
+
+const (
+	hot status = iota
+	cold
+	test
+)
+
+// ClockPro Clock with adaptive replacement cache policy.
+// Resident entries are either hot or cold. New entries start cold in their
+// test period; cold entries evicted during their test period stay in the
+// clock as non-resident test entries, and a test entry added again comes back
+// as hot while the target share of cold entries grows. Like clock, a hit only
+// sets the reference bit of the entry, so Get can be called concurrently
+// under a read lock.
+type ClockPro[K comparable, V any] struct {
+	capacity     int
+	coldTarget   int
+	hotCount     int
+	coldCount    int
+	testCount    int
+	elementMap   map[K]*list.Element
+	clockList    *list.List
+	handHot      *list.Element
+	handCold     *list.Element
+	handTest     *list.Element
+	evictHandler func(key K, value V)
+}
+
+type entry[K comparable, V any] struct {
+	key        K
+	value      V
+	status     status
+	inTest     bool
+	referenced atomic.Bool
+}
+
+// New returns new typed clockpro
+func New[K comparable, V any](capacity int) (*ClockPro[K, V], error) {
+	if capacity <= 0 {
+		return nil, errs.ErrNonPositiveCapacity
+	}
+	clockPro := &ClockPro[K, V]{
+		capacity:   capacity,
+		coldTarget: capacity,
+		elementMap: make(map[K]*list.Element),
+		clockList:  list.New(),
+	}
+	return clockPro, nil
+}
+
+// NewClockPro returns new clockpro
+func NewClockPro(capacity int) (*ClockPro[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity)
+}
+
+// Add adds entry in cache
+func (c *ClockPro[K, V]) Add(key K, value V) (eviction bool) {
+	if element, ok := c.elementMap[key]; ok {
+		existing := element.Value.(*entry[K, V])
+		if existing.status != test {
+			existing.value = value
+			existing.referenced.Store(true)
+			return false
+		}
+		c.coldTarget = min(c.coldTarget+1, c.capacity)
+		c.remove(element)
+		c.testCount--
+		eviction = c.makeRoom()
+		c.insert(&entry[K, V]{
+			key:    key,
+			value:  value,
+			status: hot,
+		})
+		c.hotCount++
+		c.balanceHot()
+		return
+	}
+	eviction = c.makeRoom()
+	c.insert(&entry[K, V]{
+		key:    key,
+		value:  value,
+		status: cold,
+		inTest: true,
+	})
+	c.coldCount++
+	return
+}
+
+// Get returns value of cached entry.
+// Get is safe to call concurrently with other Get calls.
+func (c *ClockPro[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if element, ok := c.elementMap[key]; ok {
+		entry := element.Value.(*entry[K, V])
+		if entry.status == test {
+			return value, false
+		}
+		if trigger {
+			entry.referenced.Store(true)
+		}
+		return entry.value, true
+	}
+	return
+}
+
+// ConcurrentGet reports that Get only updates atomic state.
+func (c *ClockPro[K, V]) ConcurrentGet() bool {
+	return true
+}
+
+func (c *ClockPro[K, V]) makeRoom() bool {
+	if c.Len() < c.capacity {
+		return false
+	}
+	c.runHandCold()
+	return true
+}
+
+// insert puts the entry at the head of the clock, right behind the hot hand.
+func (c *ClockPro[K, V]) insert(entry *entry[K, V]) {
+	if c.handHot == nil {
+		element := c.clockList.PushBack(entry)
+		c.elementMap[entry.key] = element
+		c.handHot, c.handCold, c.handTest = element, element, element
+		return
+	}
+	c.elementMap[entry.key] = c.clockList.InsertBefore(entry, c.handHot)
+}
+
+// moveToHead moves the element right behind the hot hand.
+func (c *ClockPro[K, V]) moveToHead(element *list.Element) {
+	if c.clockList.Len() == 1 {
+		return
+	}
+	c.skip(element)
+	c.clockList.MoveBefore(element, c.handHot)
+}
+
+// remove unlinks the element from the clock.
+func (c *ClockPro[K, V]) remove(element *list.Element) {
+	delete(c.elementMap, element.Value.(*entry[K, V]).key)
+	if c.clockList.Len() == 1 {
+		c.handHot, c.handCold, c.handTest = nil, nil, nil
+	} else {
+		c.skip(element)
+	}
+	c.clockList.Remove(element)
+}
+
+// skip moves the hands pointing to the element one step forward.
+func (c *ClockPro[K, V]) skip(element *list.Element) {
+	for _, hand := range []**list.Element{&c.handHot, &c.handCold, &c.handTest} {
+		if *hand == element {
+			*hand = c.next(element)
+		}
+	}
+}
+
+// OnEvict sets handler called with resident entries evicted due to capacity.
+// Entries kept as non-resident test entries are evicted as well.
+func (c *ClockPro[K, V]) OnEvict(handler func(key K, value V)) {
+	c.evictHandler = handler
+}
+
+// runHandCold evicts one resident entry. Referenced cold entries are given
+// another round, promoted to hot if they were in their test period.
+// Unreferenced cold entries in their test period are kept as test entries.
+func (c *ClockPro[K, V]) runHandCold() {
+	for {
+		if c.coldCount == 0 {
+			c.runHandHot()
+			continue
+		}
+		element := c.handCold
+		c.handCold = c.next(element)
+		entry := element.Value.(*entry[K, V])
+		if entry.status != cold {
+			continue
+		}
+		if entry.referenced.Swap(false) {
+			if entry.inTest {
+				entry.status = hot
+				entry.inTest = false
+				c.coldCount--
+				c.hotCount++
+				c.moveToHead(element)
+				c.balanceHot()
+			} else {
+				entry.inTest = true
+				c.moveToHead(element)
+			}
+			continue
+		}
+		c.coldCount--
+		if c.evictHandler != nil {
+			c.evictHandler(entry.key, entry.value)
+		}
+		if !entry.inTest {
+			c.remove(element)
+			return
+		}
+		var zero V
+		entry.value = zero
+		entry.status = test
+		c.testCount++
+		for c.testCount > c.capacity {
+			c.runHandTest()
+		}
+		return
+	}
+}
+
+// runHandHot turns one unreferenced hot entry into a cold one. Cold entries
+// passed by the hand end their test period and test entries are removed.
+func (c *ClockPro[K, V]) runHandHot() {
+	for {
+		element := c.handHot
+		c.handHot = c.next(element)
+		entry := element.Value.(*entry[K, V])
+		switch entry.status {
+		case hot:
+			if entry.referenced.Swap(false) {
+				continue
+			}
+			entry.status = cold
+			c.hotCount--
+			c.coldCount++
+			return
+		case cold:
+			entry.inTest = false
+		case test:
+			c.expireTest(element)
+		}
+	}
+}
+
+// runHandTest removes one test entry. Cold entries passed by the hand end
+// their test period.
+func (c *ClockPro[K, V]) runHandTest() {
+	for {
+		element := c.handTest
+		c.handTest = c.next(element)
+		entry := element.Value.(*entry[K, V])
+		switch entry.status {
+		case cold:
+			entry.inTest = false
+		case test:
+			c.expireTest(element)
+			return
+		}
+	}
+}
+
+// expireTest removes the test entry, shrinking the target of cold entries
+// since the entry was not accessed again during its test period.
+func (c *ClockPro[K, V]) expireTest(element *list.Element) {
+	c.remove(element)
+	c.testCount--
+	c.coldTarget = max(c.coldTarget-1, 1)
+}
+
+// balanceHot turns hot entries into cold ones until they fit their share.
+func (c *ClockPro[K, V]) balanceHot() {
+	for c.hotCount > 0 && c.hotCount > c.capacity-c.coldTarget {
+		c.runHandHot()
+	}
+}
+
+func (c *ClockPro[K, V]) next(element *list.Element) *list.Element {
+	if next := element.Next(); next != nil {
+		return next
+	}
+	return c.clockList.Front()
+}
+
+// Remove removes cache entry.
+func (c *ClockPro[K, V]) Remove(key K) bool {
+	element, ok := c.elementMap[key]
+	if !ok {
+		return false
+	}
+	c.remove(element)
+	switch element.Value.(*entry[K, V]).status {
+	case hot:
+		c.hotCount--
+	case cold:
+		c.coldCount--
+	case test:
+		c.testCount--
+		return false
+	}
+	return true
+}
+
+// Clear removes all entries in the cache.
+func (c *ClockPro[K, V]) Clear() int {
+	length := c.Len()
+	for key := range c.elementMap {
+		delete(c.elementMap, key)
+	}
+	c.clockList.Init()
+	c.handHot, c.handCold, c.handTest = nil, nil, nil
+	c.hotCount, c.coldCount, c.testCount = 0, 0, 0
+	c.coldTarget = c.capacity
+	return length
+}
+
+// Len returns length of the cache.
+func (c *ClockPro[K, V]) Len() int {
+	return c.hotCount + c.coldCount
+}
+
+// Cap returns capacity of the cache.
+func (c *ClockPro[K, V]) Cap() int {
+	return c.capacity
+}
+
+// SetCap set capacity of the cache.
+// Returns error unless newCap is negative value.
+func (c *ClockPro[K, V]) SetCap(newCapacity int) error {
+	if newCapacity <= 0 {
+		return errs.ErrNonPositiveCapacity
+	}
+	c.capacity = newCapacity
+	c.coldTarget = min(c.coldTarget, newCapacity)
+	for c.Len() > newCapacity {
+		c.runHandCold()
+	}
+	for c.testCount > newCapacity {
+		c.runHandTest()
+	}
+	c.balanceHot()
+	return nil
+}
+
+// Keys returns a slice of entry keys in the cache.
+func (c *ClockPro[K, V]) Keys() []K {
+	keys := make([]K, 0, c.Len())
+	for k, v := range c.elementMap {
+		if v.Value.(*entry[K, V]).status != test {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Values returns a slice of entry values in the cache.
+func (c *ClockPro[K, V]) Values() []V {
+	values := make([]V, 0, c.Len())
+	for _, v := range c.elementMap {
+		if entry := v.Value.(*entry[K, V]); entry.status != test {
+			values = append(values, entry.value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,344 @@
+package clockpro
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestNewClockPro(t *testing.T) {
+	clockPro, err := NewClockPro(1)
+	if clockPro == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	clockPro, err = NewClockPro(0)
+	if clockPro != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	clockPro, err = NewClockPro(-1)
+	if clockPro != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNew(t *testing.T) {
+	clockPro, err := New[string, int](1)
+	if clockPro == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	clockPro.Add("1", 1)
+	if value, ok := clockPro.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	clockPro, err = New[string, int](0)
+	if clockPro != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestClockPro_Add(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	for i := 0; i < capacity*2; i++ {
+		clockPro.Add(i, strconv.Itoa(i))
+	}
+}
+
+func TestClockPro_Add2(t *testing.T) {
+	capacity := 3
+	clockPro, _ := NewClockPro(capacity)
+	clockPro.Add(1, nil)
+	clockPro.Add(2, nil)
+	clockPro.Add(3, nil)
+	if !containsAll(clockPro.Keys(), 1, 2, 3) || clockPro.coldCount != 3 {
+		t.FailNow()
+	}
+	clockPro.Add(4, nil)
+	// one of the cold entries becomes a test entry
+	if clockPro.Len() != capacity || clockPro.testCount != 1 {
+		t.FailNow()
+	}
+	var evicted interface{}
+	for _, key := range []interface{}{1, 2, 3} {
+		if _, ok := clockPro.Get(key, false); !ok {
+			evicted = key
+		}
+	}
+	if evicted == nil {
+		t.FailNow()
+	}
+	// adding a test entry again brings it back
+	clockPro.Add(evicted, nil)
+	if !contains(clockPro.Keys(), evicted) || clockPro.Len() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestClockPro_Adapt(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	for i := 0; i < capacity*10; i++ {
+		clockPro.Add(i, nil)
+	}
+	// test entries expiring without being accessed shrink the cold target
+	if clockPro.coldTarget != 1 || clockPro.testCount > capacity {
+		t.FailNow()
+	}
+	for i := capacity * 9; i < capacity*10; i++ {
+		clockPro.Add(i-capacity, nil)
+	}
+	// test entries added again come back as hot
+	if clockPro.hotCount == 0 {
+		t.FailNow()
+	}
+}
+
+func TestClockPro_Get(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	for i := 0; i < capacity; i++ {
+		clockPro.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		value, ok := clockPro.Get(i, true)
+		if !ok || value.(string) != strconv.Itoa(i) {
+			t.FailNow()
+		}
+	}
+	for i := capacity; i < capacity*2; i++ {
+		value, ok := clockPro.Get(i, true)
+		if ok || value != nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClockPro_Remove(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	for i := 0; i < capacity; i++ {
+		clockPro.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		ok := clockPro.Remove(i)
+		if !ok {
+			t.FailNow()
+		}
+	}
+	for i := 0; i < capacity; i++ {
+		ok := clockPro.Remove(i)
+		if ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClockPro_Clear(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	for i := 0; i < capacity; i++ {
+		clockPro.Add(i, strconv.Itoa(i))
+	}
+	clockPro.Clear()
+	if clockPro.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestClockPro_Cap(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	if clockPro.Cap() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestClockPro_Len(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	if clockPro.Len() != 0 {
+		t.FailNow()
+	}
+	for i := 0; i < capacity*2; i++ {
+		clockPro.Add(i, strconv.Itoa(i))
+		if clockPro.Len() != int(math.Min(float64(i+1), float64(capacity))) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClockPro_SetCap(t *testing.T) {
+	capacity := 10
+	newCapacity := 20
+	clockPro, _ := NewClockPro(capacity)
+	err := clockPro.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if clockPro.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = 5
+	err = clockPro.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if clockPro.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = -1
+	err = clockPro.SetCap(newCapacity)
+	if err == nil {
+		t.FailNow()
+	}
+	if clockPro.Cap() == newCapacity {
+		t.FailNow()
+	}
+}
+
+func TestClockPro_Keys(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	for i := 0; i < capacity*2; i++ {
+		clockPro.Add(i, strconv.Itoa(i))
+		keys := clockPro.Keys()
+		if !contains(keys, i) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClockPro_Values(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	for i := 0; i < capacity*2; i++ {
+		clockPro.Add(i, strconv.Itoa(i))
+		values := clockPro.Values()
+		if !contains(values, strconv.Itoa(i)) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClockPro_Random(t *testing.T) {
+	capacity := 50
+	clockPro, _ := NewClockPro(capacity)
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		key := random.Intn(capacity * 4)
+		switch random.Intn(10) {
+		case 0:
+			clockPro.Remove(key)
+		case 1, 2, 3:
+			clockPro.Get(key, true)
+		default:
+			clockPro.Add(key, key)
+		}
+		if i%1000 == 0 {
+			clockPro.SetCap(capacity/2 + random.Intn(capacity))
+		}
+		if clockPro.Len() > clockPro.Cap() || clockPro.testCount > clockPro.Cap() {
+			t.Fatal("A", i, clockPro.Len(), clockPro.testCount, clockPro.Cap(), clockPro.hotCount, clockPro.coldCount)
+		}
+		if clockPro.Len()+clockPro.testCount != len(clockPro.elementMap) ||
+			len(clockPro.elementMap) != clockPro.clockList.Len() {
+			t.Fatal("B", i)
+		}
+	}
+	for _, key := range clockPro.Keys() {
+		if value, ok := clockPro.Get(key, false); !ok || value != key {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClockPro_Scan(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	for i := 0; i < capacity/2; i++ {
+		clockPro.Add(i, nil)
+		clockPro.Get(i, true)
+	}
+	for i := capacity; i < capacity*10; i++ {
+		clockPro.Add(i, nil)
+		for j := 0; j < capacity/2; j++ {
+			clockPro.Get(j, true)
+		}
+	}
+	for i := 0; i < capacity/2; i++ {
+		if _, ok := clockPro.Get(i, false); !ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestClockPro_ConcurrentGet(t *testing.T) {
+	capacity := 10
+	clockPro, _ := NewClockPro(capacity)
+	if !clockPro.ConcurrentGet() {
+		t.FailNow()
+	}
+}
+
+func contains(s []interface{}, e interface{}) bool {
+	for _, c := range s {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(s []interface{}, es ...interface{}) bool {
+	for _, e := range es {
+		if !contains(s, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestClockPro_OnEvict(t *testing.T) {
+	capacity := 10
+	clockpro, _ := NewClockPro(capacity)
+	evicted := make([]interface{}, 0)
+	clockpro.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		clockpro.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	clockpro.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || clockpro.Len() != capacity {
+		t.FailNow()
+	}
+	if _, ok := clockpro.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	clockpro.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || clockpro.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
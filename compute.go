@@ -0,0 +1,123 @@
+package nucleus
+
+// Compute computes value of the key from its current value atomically.
+// fn is called under the lock with the current value and whether the entry
+// exists, it must not call methods of the cache. If fn returns keep, its
+// value is added or replaces the entry, otherwise the entry is removed.
+// Returns the value in the cache and whether it is kept, which is false if
+// the policy does not admit the value.
+func (c *Cache[K, V]) Compute(key K, fn func(old V, exists bool) (value V, keep bool)) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.unlock()
+	old, exists := c.policy.Get(key, false)
+	value, keep := fn(old, exists)
+	return c.apply(key, old, exists, value, keep)
+}
+
+// ComputeIfAbsent returns value of the entry, or computes it by fn and adds
+// it in the cache if fn returns keep. Existing entry is accessed as in Get.
+// Returns the value in the cache and whether there is an entry.
+func (c *Cache[K, V]) ComputeIfAbsent(key K, fn func() (value V, keep bool)) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.unlock()
+	if value, ok = c.policy.Get(key, true); ok {
+		return
+	}
+	value, keep := fn()
+	var old V
+	return c.apply(key, old, false, value, keep)
+}
+
+// ComputeIfPresent computes value of an existing entry from its current
+// value. If fn returns keep, its value replaces the entry, otherwise the
+// entry is removed.
+// Returns the value in the cache and whether it is kept.
+func (c *Cache[K, V]) ComputeIfPresent(key K, fn func(old V) (value V, keep bool)) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.unlock()
+	old, exists := c.policy.Get(key, false)
+	if !exists {
+		return
+	}
+	value, keep := fn(old)
+	return c.apply(key, old, true, value, keep)
+}
+
+// Merge adds the value if there is no entry of the key, otherwise merges it
+// into the current value by fn. If fn returns keep, the merged value
+// replaces the entry, otherwise the entry is removed.
+// Returns the value in the cache and whether it is kept.
+func (c *Cache[K, V]) Merge(key K, value V, fn func(old, value V) (merged V, keep bool)) (V, bool) {
+	c.lock.Lock()
+	defer c.unlock()
+	old, exists := c.policy.Get(key, false)
+	if !exists {
+		return c.apply(key, old, false, value, true)
+	}
+	merged, keep := fn(old, value)
+	return c.apply(key, old, true, merged, keep)
+}
+
+// CompareAndSwap replaces value of the entry with new if it equals to old.
+// Like sync.Map, it panics if the values are not comparable.
+// Returns true if the value is swapped.
+func (c *Cache[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	c.lock.Lock()
+	defer c.unlock()
+	current, exists := c.policy.Get(key, false)
+	if !exists || any(current) != any(old) {
+		return false
+	}
+	c.apply(key, current, true, new, true)
+	return true
+}
+
+// apply adds or removes the computed value, the cache must be locked by the
+// caller. A value which the policy does not admit, as tinylfu may reject new
+// entries, is not kept.
+func (c *Cache[K, V]) apply(key K, old V, exists bool, value V, keep bool) (V, bool) {
+	if keep {
+		c.policy.Add(key, value)
+		c.recordPut(key, old, exists)
+		if _, ok := c.policy.Get(key, false); !ok {
+			var zero V
+			return zero, false
+		}
+		return value, true
+	}
+	if exists {
+		c.policy.Remove(key)
+		c.recordEviction(key, old, EvictRemoved)
+		c.loadGroup.forget(key)
+	}
+	var zero V
+	return zero, false
+}
+
+// Compute computes value of the key in its shard atomically.
+func (s *ShardedCache[K, V]) Compute(key K, fn func(old V, exists bool) (value V, keep bool)) (value V, ok bool) {
+	return s.shard(key).Compute(key, fn)
+}
+
+// ComputeIfAbsent returns value of the entry, or computes it in the shard of
+// the key.
+func (s *ShardedCache[K, V]) ComputeIfAbsent(key K, fn func() (value V, keep bool)) (value V, ok bool) {
+	return s.shard(key).ComputeIfAbsent(key, fn)
+}
+
+// ComputeIfPresent computes value of an existing entry in the shard of the
+// key.
+func (s *ShardedCache[K, V]) ComputeIfPresent(key K, fn func(old V) (value V, keep bool)) (value V, ok bool) {
+	return s.shard(key).ComputeIfPresent(key, fn)
+}
+
+// Merge adds or merges the value in the shard of the key.
+func (s *ShardedCache[K, V]) Merge(key K, value V, fn func(old, value V) (merged V, keep bool)) (V, bool) {
+	return s.shard(key).Merge(key, value, fn)
+}
+
+// CompareAndSwap replaces value of the entry in the shard of the key with
+// new if it equals to old.
+func (s *ShardedCache[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	return s.shard(key).CompareAndSwap(key, old, new)
+}
@@ -0,0 +1,212 @@
+package nucleus
+
+import (
+	"github.com/SemihBKGR/nucleus/lru"
+	"sync"
+	"testing"
+)
+
+func TestCache_Compute(t *testing.T) {
+	capacity := 2
+	cache, _ := NewLruCache(capacity)
+	evictions := recordEvictions(cache)
+	increment := func(old interface{}, exists bool) (interface{}, bool) {
+		if !exists {
+			return 1, true
+		}
+		return old.(int) + 1, true
+	}
+	cache.Compute(1, increment)
+	if value, ok := cache.Compute(1, increment); !ok || value != 2 {
+		t.FailNow()
+	}
+	cache.Add(2, 0)
+	// computing moves the entry to the front
+	cache.Compute(1, increment)
+	cache.Add(3, 0)
+	if !cache.Contains(1) || cache.Contains(2) {
+		t.FailNow()
+	}
+	if value, ok := cache.Compute(1, func(old interface{}, exists bool) (interface{}, bool) {
+		return nil, false
+	}); ok || value != nil || cache.Contains(1) {
+		t.FailNow()
+	}
+	e := evictions()
+	if len(e) != 4 || e[2] != (evicted{2, 0, EvictCapacity}) || e[3] != (evicted{1, 3, EvictRemoved}) {
+		t.Fatal(e)
+	}
+	if stats := cache.Stats(); stats.Adds != 3 || stats.Updates != 2 {
+		t.FailNow()
+	}
+}
+
+func TestCache_Compute2(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLfuCache(capacity)
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cache.Merge("counter", 1, func(old, value interface{}) (interface{}, bool) {
+					return old.(int) + value.(int), true
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if value, _ := cache.Get("counter"); value != 800 {
+		t.FailNow()
+	}
+}
+
+func TestCache_ComputeIfAbsent(t *testing.T) {
+	capacity := 2
+	cache, _ := NewLruCache(capacity)
+	calls := 0
+	fn := func() (interface{}, bool) {
+		calls++
+		return "1", true
+	}
+	if value, ok := cache.ComputeIfAbsent(1, fn); !ok || value != "1" {
+		t.FailNow()
+	}
+	cache.Add(2, "2")
+	if value, ok := cache.ComputeIfAbsent(1, fn); !ok || value != "1" || calls != 1 {
+		t.FailNow()
+	}
+	// existing entry is accessed
+	cache.Add(3, "3")
+	if !cache.Contains(1) || cache.Contains(2) {
+		t.FailNow()
+	}
+	if _, ok := cache.ComputeIfAbsent(4, func() (interface{}, bool) {
+		return nil, false
+	}); ok || cache.Contains(4) {
+		t.FailNow()
+	}
+}
+
+func TestCache_ComputeIfPresent(t *testing.T) {
+	capacity := 2
+	cache, _ := NewLruCache(capacity)
+	fn := func(old interface{}) (interface{}, bool) {
+		return old.(string) + "!", true
+	}
+	if _, ok := cache.ComputeIfPresent(1, fn); ok || cache.Contains(1) {
+		t.FailNow()
+	}
+	cache.Add(1, "1")
+	if value, ok := cache.ComputeIfPresent(1, fn); !ok || value != "1!" {
+		t.FailNow()
+	}
+	if _, ok := cache.ComputeIfPresent(1, func(old interface{}) (interface{}, bool) {
+		return old, false
+	}); ok || cache.Contains(1) {
+		t.FailNow()
+	}
+}
+
+func TestCache_Merge(t *testing.T) {
+	capacity := 2
+	cache, _ := NewLruCache(capacity)
+	evictions := recordEvictions(cache)
+	appendFn := func(old, value interface{}) (interface{}, bool) {
+		return append(old.([]int), value.([]int)...), true
+	}
+	cache.Merge(1, []int{1}, appendFn)
+	if value, ok := cache.Merge(1, []int{2}, appendFn); !ok || len(value.([]int)) != 2 {
+		t.FailNow()
+	}
+	if _, ok := cache.Merge(1, []int{3}, func(old, value interface{}) (interface{}, bool) {
+		return nil, false
+	}); ok || cache.Contains(1) {
+		t.FailNow()
+	}
+	if e := evictions(); len(e) != 2 || e[0].reason != EvictReplaced || e[1].reason != EvictRemoved {
+		t.FailNow()
+	}
+}
+
+func TestCache_CompareAndSwap(t *testing.T) {
+	capacity := 2
+	cache, _ := NewLruCache(capacity)
+	if cache.CompareAndSwap(1, nil, "1") {
+		t.FailNow()
+	}
+	cache.Add(1, "1")
+	if cache.CompareAndSwap(1, "2", "3") {
+		t.FailNow()
+	}
+	if !cache.CompareAndSwap(1, "1", "2") {
+		t.FailNow()
+	}
+	if value, _ := cache.Get(1); value != "2" {
+		t.FailNow()
+	}
+	sharded, _ := NewShardedCache(2, capacity, NewLruCache)
+	sharded.Add(1, 1)
+	if !sharded.CompareAndSwap(1, 1, 2) {
+		t.FailNow()
+	}
+	if value, ok := sharded.Compute(1, func(old interface{}, exists bool) (interface{}, bool) {
+		return old.(int) * 10, exists
+	}); !ok || value != 20 {
+		t.FailNow()
+	}
+}
+
+// rejectingPolicy does not admit new entries of odd keys.
+type rejectingPolicy struct {
+	Policy[int, int]
+}
+
+func (p rejectingPolicy) Add(key int, value int) bool {
+	if _, ok := p.Get(key, false); !ok && key%2 == 1 {
+		return false
+	}
+	return p.Policy.Add(key, value)
+}
+
+func TestCache_Compute_Admission(t *testing.T) {
+	capacity := 2
+	lruPolicy, _ := lru.New[int, int](capacity)
+	cache, _ := NewWithPolicy[int, int](rejectingPolicy{lruPolicy})
+	// the computed value is not kept if the policy does not admit it
+	if value, ok := cache.Compute(1, func(old int, exists bool) (int, bool) {
+		return 5, true
+	}); ok || value != 0 || cache.Contains(1) {
+		t.FailNow()
+	}
+	if value, ok := cache.ComputeIfAbsent(3, func() (int, bool) {
+		return 5, true
+	}); ok || value != 0 || cache.Contains(3) {
+		t.FailNow()
+	}
+	if value, ok := cache.Compute(2, func(old int, exists bool) (int, bool) {
+		return 5, true
+	}); !ok || value != 5 || !cache.Contains(2) {
+		t.FailNow()
+	}
+}
+
+func TestCache_Compute_TinyLfu(t *testing.T) {
+	capacity := 1
+	cache, _ := NewTinyLfu[int, int](capacity)
+	for i := 0; i < 5; i++ {
+		cache.Add(1, 1)
+	}
+	value, ok := cache.Compute(2, func(old int, exists bool) (int, bool) {
+		return 5, true
+	})
+	if current, contains := cache.Get(2); ok != contains || value != current {
+		t.FailNow()
+	}
+	if value, ok := cache.Compute(2, func(old int, exists bool) (int, bool) {
+		return old + 1, true
+	}); !ok || value != 6 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,134 @@
+package nucleus
+
+import (
+	"fmt"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+)
+
+// Weigher returns cost of the entry, such as its size in bytes.
+type Weigher[K comparable, V any] func(key K, value V) int64
+
+// WeightedPolicy is implemented by policies bounding total cost of entries
+// besides their count.
+type WeightedPolicy[K comparable, V any] interface {
+	AddWithCost(key K, value V, cost int64) (eviction bool)
+	SetWeigher(weigher func(key K, value V) int64)
+	Cost() int64
+	MaxCost() int64
+	SetMaxCost(maxCost int64) error
+}
+
+// AddWithCost adds entry in cache with given cost.
+// Entries are evicted until total cost fits max cost.
+// Returns error if cost is negative value or policy is not a WeightedPolicy.
+func (c *Cache[K, V]) AddWithCost(key K, value V, cost int64) (eviction bool, err error) {
+	if cost < 0 {
+		return false, fmt.Errorf("%w: must not be negative value", ErrInvalidCost)
+	}
+	policy, ok := c.policy.(WeightedPolicy[K, V])
+	if !ok {
+		return false, unsupported("cost")
+	}
+	c.lock.Lock()
+	defer c.unlock()
+	old, exists := c.policy.Get(key, false)
+	eviction = policy.AddWithCost(key, value, cost)
+	c.recordPut(key, old, exists)
+	return eviction, nil
+}
+
+// SetWeigher sets weigher giving cost of entries added without explicit cost.
+// Costs of the entries already in the cache are not changed.
+// Returns error if policy is not a WeightedPolicy.
+func (c *Cache[K, V]) SetWeigher(weigher Weigher[K, V]) error {
+	policy, ok := c.policy.(WeightedPolicy[K, V])
+	if !ok {
+		return unsupported("cost")
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	policy.SetWeigher(weigher)
+	return nil
+}
+
+// Cost returns total cost of the entries in the cache.
+// It equals to Len for policies which are not WeightedPolicy.
+func (c *Cache[K, V]) Cost() int64 {
+	c.readLock()
+	defer c.readUnlock()
+	if policy, ok := c.policy.(WeightedPolicy[K, V]); ok {
+		return policy.Cost()
+	}
+	return int64(c.policy.Len())
+}
+
+// MaxCost returns max total cost of the cache, zero if unbounded.
+func (c *Cache[K, V]) MaxCost() int64 {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if policy, ok := c.policy.(WeightedPolicy[K, V]); ok {
+		return policy.MaxCost()
+	}
+	return 0
+}
+
+// SetMaxCost set max total cost of the cache, zero for unbounded.
+// Returns error if maxCost is negative value or policy is not a WeightedPolicy.
+func (c *Cache[K, V]) SetMaxCost(maxCost int64) error {
+	policy, ok := c.policy.(WeightedPolicy[K, V])
+	if !ok {
+		return unsupported("cost")
+	}
+	return c.resize(func() error {
+		return policy.SetMaxCost(maxCost)
+	})
+}
+
+// AddWithCost adds entry in the shard of the key with given cost.
+// Max cost is bounded by every shard separately.
+func (s *ShardedCache[K, V]) AddWithCost(key K, value V, cost int64) (eviction bool, err error) {
+	return s.shard(key).AddWithCost(key, value, cost)
+}
+
+// SetWeigher sets weigher of every shard.
+func (s *ShardedCache[K, V]) SetWeigher(weigher Weigher[K, V]) error {
+	for _, shard := range s.shards {
+		if err := shard.SetWeigher(weigher); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cost returns total cost of the entries in all shards.
+func (s *ShardedCache[K, V]) Cost() (cost int64) {
+	for _, shard := range s.shards {
+		cost += shard.Cost()
+	}
+	return
+}
+
+// MaxCost returns sum of max total costs of the shards.
+func (s *ShardedCache[K, V]) MaxCost() (maxCost int64) {
+	for _, shard := range s.shards {
+		maxCost += shard.MaxCost()
+	}
+	return
+}
+
+// SetMaxCost distributes max total cost across shards.
+// Returns error if maxCost is negative value or less than shard count.
+func (s *ShardedCache[K, V]) SetMaxCost(maxCost int64) error {
+	if maxCost < 0 {
+		return errs.ErrNegativeMaxCost
+	}
+	if maxCost > 0 && maxCost < int64(len(s.shards)) {
+		return fmt.Errorf("%w: max cost must not be less than shard count", ErrInvalidCost)
+	}
+	for i, shard := range s.shards {
+		if err := shard.SetMaxCost(int64(shardCap(int(maxCost), len(s.shards), i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
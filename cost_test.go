@@ -0,0 +1,115 @@
+package nucleus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_AddWithCost(t *testing.T) {
+	capacity := 100
+	cache, _ := NewLruCache(capacity)
+	_ = cache.SetMaxCost(10)
+	evictions := recordEvictions(cache)
+	if _, err := cache.AddWithCost(1, "1", -1); err == nil {
+		t.FailNow()
+	}
+	_, _ = cache.AddWithCost(1, "1", 6)
+	eviction, err := cache.AddWithCost(2, "2", 6)
+	if err != nil || !eviction || cache.Contains(1) || cache.Cost() != 6 {
+		t.FailNow()
+	}
+	if e := evictions(); len(e) != 1 || e[0] != (evicted{1, "1", EvictCapacity}) {
+		t.FailNow()
+	}
+	// entries costlier than max cost are evicted right away
+	_, _ = cache.AddWithCost(2, "two", 11)
+	if cache.Contains(2) || cache.Cost() != 0 {
+		t.FailNow()
+	}
+	e := evictions()
+	if len(e) != 2 || e[0] != (evicted{2, "two", EvictCapacity}) || e[1] != (evicted{2, "2", EvictReplaced}) {
+		t.FailNow()
+	}
+	clockCache, _ := NewClockCache(capacity)
+	if _, err := clockCache.AddWithCost(1, "1", 1); err == nil || clockCache.Contains(1) {
+		t.FailNow()
+	}
+}
+
+func TestCache_SetWeigher(t *testing.T) {
+	capacity := 100
+	cache, _ := NewTlruCache(capacity, time.Hour)
+	defer cache.Close()
+	weigher := func(key, value interface{}) int64 {
+		return int64(len(value.(string)))
+	}
+	if cache.SetWeigher(weigher) != nil {
+		t.FailNow()
+	}
+	cache.Add(1, strings.Repeat("a", 5))
+	_, _ = cache.AddWithTTL(2, strings.Repeat("a", 3), time.Hour)
+	cache.Set(2, strings.Repeat("a", 4))
+	if cache.Cost() != 9 || cache.Len() != 2 {
+		t.FailNow()
+	}
+	clockCache, _ := NewClockCache(capacity)
+	if clockCache.SetWeigher(weigher) == nil {
+		t.FailNow()
+	}
+}
+
+func TestCache_SetMaxCost(t *testing.T) {
+	capacity := 100
+	cache, _ := NewFifoCache(capacity)
+	evictions := recordEvictions(cache)
+	for i := 0; i < 10; i++ {
+		cache.Add(i, i)
+	}
+	if cache.Cost() != 10 || cache.MaxCost() != 0 {
+		t.FailNow()
+	}
+	if cache.SetMaxCost(-1) == nil {
+		t.FailNow()
+	}
+	if cache.SetMaxCost(4) != nil || cache.Cost() != 4 || cache.MaxCost() != 4 {
+		t.FailNow()
+	}
+	e := evictions()
+	if len(e) != 6 {
+		t.FailNow()
+	}
+	for i, eviction := range e {
+		if eviction != (evicted{i, i, EvictResized}) {
+			t.FailNow()
+		}
+	}
+	clockCache, _ := NewClockCache(capacity)
+	clockCache.Add(1, 1)
+	if clockCache.SetMaxCost(1) == nil || clockCache.Cost() != 1 || clockCache.MaxCost() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestShardedCache_SetMaxCost(t *testing.T) {
+	capacity := 100
+	sharded, _ := NewShardedCache(4, capacity, NewLruCache)
+	if sharded.SetMaxCost(-1) == nil || sharded.SetMaxCost(3) == nil {
+		t.FailNow()
+	}
+	if sharded.SetMaxCost(40) != nil || sharded.MaxCost() != 40 {
+		t.FailNow()
+	}
+	_ = sharded.SetWeigher(func(key, value interface{}) int64 {
+		return 2
+	})
+	for i := 0; i < capacity; i++ {
+		sharded.Add(i, i)
+	}
+	if sharded.Cost() > 40 || sharded.Cost() != int64(2*sharded.Len()) {
+		t.FailNow()
+	}
+	if _, err := sharded.AddWithCost(1, 1, 1); err != nil {
+		t.FailNow()
+	}
+}
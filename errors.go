@@ -0,0 +1,56 @@
+package nucleus
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+)
+
+// Errors returned by the caches and the policies, they are matched with
+// errors.Is as they may be wrapped with details. Operations which are not
+// supported by the policy return errors wrapping errors.ErrUnsupported.
+var (
+	// ErrInvalidCapacity is returned for capacities which are not positive
+	// value, or less than shard count.
+	ErrInvalidCapacity = errs.ErrInvalidCapacity
+	// ErrInvalidTTL is returned for ttl and durations of expiration which
+	// are out of range.
+	ErrInvalidTTL = errs.ErrInvalidTTL
+	// ErrInvalidCost is returned for negative costs and max costs.
+	ErrInvalidCost = errs.ErrInvalidCost
+	// ErrInvalidArgument is returned for other arguments which are out of
+	// range, such as worker counts, shard counts and ratios of the segments.
+	ErrInvalidArgument = errs.ErrInvalidArgument
+	// ErrClosed is returned by operations starting background goroutines
+	// after the cache is closed.
+	ErrClosed = errors.New("cache is closed")
+	// ErrNotFound may be returned by loaders for keys which do not exist,
+	// it is also returned for keys left out by batch loaders.
+	ErrNotFound = errors.New("not found")
+	// ErrLoaderPanicked is returned to callers waiting for a loader which
+	// panicked.
+	ErrLoaderPanicked = errors.New("loader panicked")
+)
+
+// LoadError is returned by GetOrLoad and GetOrLoadMany if loading the key
+// fails, wrapping the error of the loader.
+type LoadError struct {
+	Key any
+	Err error
+}
+
+// Error returns the key and the error of the loader.
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("nucleus: load %v: %v", e.Key, e.Err)
+}
+
+// Unwrap returns the error of the loader.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// unsupported returns error of operation which is not supported by the
+// policy.
+func unsupported(operation string) error {
+	return fmt.Errorf("policy does not support %s: %w", operation, errors.ErrUnsupported)
+}
@@ -0,0 +1,161 @@
+package nucleus
+
+import (
+	"context"
+	"errors"
+	"github.com/SemihBKGR/nucleus/fifo"
+	"github.com/SemihBKGR/nucleus/lru"
+	"github.com/SemihBKGR/nucleus/mru"
+	"github.com/SemihBKGR/nucleus/tlru"
+	"github.com/SemihBKGR/nucleus/twoq"
+	"testing"
+	"time"
+)
+
+func TestErrInvalidCapacity(t *testing.T) {
+	_, lruErr := lru.NewLru(0)
+	_, mruErr := mru.NewMru(0)
+	_, fifoErr := fifo.NewFifo(-1)
+	_, tlruErr := tlru.NewTlru(0, time.Second)
+	lruPolicy, _ := lru.NewLru(1)
+	_, cacheErr := NewLruCache(0)
+	_, shardedErr := NewShardedCache(4, 2, NewLruCache)
+	for _, err := range []error{
+		lruErr, mruErr, fifoErr, tlruErr, lruPolicy.SetCap(0), cacheErr, shardedErr,
+	} {
+		if !errors.Is(err, ErrInvalidCapacity) {
+			t.Fatal(err)
+		}
+	}
+	var optionErr *OptionError
+	if _, err := New[int, int](WithCapacity(0)); !errors.As(err, &optionErr) || optionErr.Option != "WithCapacity" {
+		t.FailNow()
+	}
+	if errors.As(cacheErr, &optionErr) {
+		t.FailNow()
+	}
+}
+
+func TestErrInvalidTTL(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCache(capacity, time.Hour)
+	defer cache.Close()
+	_, addErr := cache.AddWithTTL(1, 1, -time.Second)
+	_, optionErr := New[int, int](WithCapacity(capacity), WithTTL(-time.Second))
+	_, sweepErr := New[int, int](WithCapacity(capacity), WithTTL(time.Second), WithSweepInterval(-time.Second))
+	tlruPolicy, _ := tlru.NewTlru(capacity, time.Hour)
+	for _, err := range []error{
+		addErr, optionErr, cache.SetNegativeTTL(-time.Second), cache.SetRefresh(-time.Second, 1, nil),
+		sweepErr, tlruPolicy.SetSweepInterval(0),
+	} {
+		if !errors.Is(err, ErrInvalidTTL) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestErrInvalidArgument(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCache(capacity, time.Hour)
+	defer cache.Close()
+	loader := func(ctx context.Context, key interface{}) (interface{}, error) {
+		return key, nil
+	}
+	_, shardedErr := NewShardedCache(0, capacity, NewLruCache)
+	_, twoQErr := NewTwoQCache(capacity, 1, twoq.DefaultGhostRatio)
+	_, slruErr := NewSlruCache(capacity, 0)
+	for _, err := range []error{
+		cache.SetRefresh(time.Second, 0, loader), cache.SetRefresh(time.Second, 1, nil),
+		shardedErr, twoQErr, slruErr,
+	} {
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestErrInvalidCost(t *testing.T) {
+	capacity := 10
+	cache, _ := NewFifoCache(capacity)
+	_, addErr := cache.AddWithCost(1, 1, -1)
+	sharded, _ := NewShardedCache(4, capacity, NewMruCache)
+	tlruPolicy, _ := tlru.NewTlru(capacity, time.Hour)
+	for _, err := range []error{
+		addErr, cache.SetMaxCost(-1), sharded.SetMaxCost(2), tlruPolicy.SetMaxCost(-1),
+	} {
+		if !errors.Is(err, ErrInvalidCost) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestErrUnsupported(t *testing.T) {
+	capacity := 10
+	cache, _ := NewArcCache(capacity)
+	_, ttlErr := cache.AddWithTTL(1, 1, time.Second)
+	_, costErr := cache.AddWithCost(1, 1, 1)
+	_, optionErr := New[int, int](WithPolicy(LruPolicy), WithCapacity(capacity), WithTTL(time.Second))
+	for _, err := range []error{
+		ttlErr, costErr, cache.SetMaxCost(1), optionErr, cache.SetRefresh(time.Second, 1, nil),
+	} {
+		if !errors.Is(err, errors.ErrUnsupported) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestErrClosed(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCache(capacity, time.Hour)
+	_ = cache.Close()
+	err := cache.SetRefresh(time.Second, 1, func(ctx context.Context, key interface{}) (interface{}, error) {
+		return key, nil
+	})
+	if !errors.Is(err, ErrClosed) {
+		t.FailNow()
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLruCache(capacity)
+	loadErr := errors.New("load failed")
+	_, err := cache.GetOrLoad(context.Background(), 1, func(ctx context.Context, key interface{}) (interface{}, error) {
+		return nil, loadErr
+	})
+	var e *LoadError
+	if !errors.As(err, &e) || e.Key != 1 || !errors.Is(err, loadErr) {
+		t.FailNow()
+	}
+	if err.Error() != "nucleus: load 1: load failed" {
+		t.FailNow()
+	}
+	values, err := cache.GetOrLoadMany(context.Background(), []interface{}{1, 2}, func(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+		return nil, loadErr
+	})
+	if len(values) != 0 || !errors.As(err, &e) || !errors.Is(err, loadErr) {
+		t.FailNow()
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _ = cache.GetOrLoadMany(context.Background(), []interface{}{3}, func(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+	}()
+	<-started
+	done := make(chan error)
+	go func() {
+		_, err := cache.GetOrLoad(context.Background(), 3, func(ctx context.Context, key interface{}) (interface{}, error) {
+			return key, nil
+		})
+		done <- err
+	}()
+	time.Sleep(time.Millisecond)
+	close(release)
+	if err := <-done; err != nil && !errors.Is(err, ErrNotFound) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,157 @@
+package nucleus
+
+// EvictReason describes why an entry left the cache.
+type EvictReason int
+
+const (
+	// EvictCapacity is reported for entries evicted by the policy to make room for new ones.
+	EvictCapacity EvictReason = iota
+	// EvictExpired is reported for entries removed after their ttl passed.
+	EvictExpired
+	// EvictRemoved is reported for entries removed by Remove.
+	EvictRemoved
+	// EvictCleared is reported for entries removed by Clear.
+	EvictCleared
+	// EvictReplaced is reported for old values of entries overwritten by Add or Set.
+	EvictReplaced
+	// EvictResized is reported for entries evicted because SetCap shrank the cache.
+	EvictResized
+)
+
+// String returns name of the reason.
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictRemoved:
+		return "removed"
+	case EvictCleared:
+		return "cleared"
+	case EvictReplaced:
+		return "replaced"
+	case EvictResized:
+		return "resized"
+	}
+	return "unknown"
+}
+
+// EvictionCallback is called with entries leaving the cache.
+type EvictionCallback[K comparable, V any] func(key K, value V, reason EvictReason)
+
+// EvictingPolicy is implemented by policies reporting entries they evict due
+// to capacity.
+type EvictingPolicy[K comparable, V any] interface {
+	OnEvict(handler func(key K, value V))
+}
+
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// cacheLocker locks the cache for background goroutines of the policy and
+// notifies the evictions recorded under the lock in a new goroutine when
+// unlocked.
+type cacheLocker[K comparable, V any] struct {
+	cache *Cache[K, V]
+}
+
+func (l cacheLocker[K, V]) Lock() {
+	l.cache.lock.Lock()
+}
+
+func (l cacheLocker[K, V]) Unlock() {
+	l.cache.unlockAsync()
+}
+
+// OnEvict sets callback called with entries leaving the cache and the reason.
+// Callbacks run after the cache lock is released, in the goroutine which
+// caused the eviction, or in a new goroutine for evictions caused by
+// background goroutines such as the tlru expiration daemon and refresh
+// workers. So callbacks may call the cache, including Close, and may run
+// concurrently.
+// Nil callback disables notifications.
+func (c *Cache[K, V]) OnEvict(callback EvictionCallback[K, V]) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.onEvict = callback
+}
+
+// OnEvict sets callback of every shard.
+func (s *ShardedCache[K, V]) OnEvict(callback EvictionCallback[K, V]) {
+	for _, shard := range s.shards {
+		shard.OnEvict(callback)
+	}
+}
+
+// hookPolicy registers the handlers recording evictions of the policy.
+func (c *Cache[K, V]) hookPolicy() {
+	if policy, ok := c.policy.(EvictingPolicy[K, V]); ok {
+		policy.OnEvict(func(key K, value V) {
+			c.recordEviction(key, value, c.evictReason)
+		})
+	}
+	if policy, ok := c.policy.(ExpirablePolicy[K, V]); ok {
+		policy.OnExpire(func(key K, value V) {
+			c.recordEviction(key, value, EvictExpired)
+		})
+	}
+}
+
+// recordEviction counts the eviction and buffers it until the write lock is
+// released.
+func (c *Cache[K, V]) recordEviction(key K, value V, reason EvictReason) {
+	c.stats.recordEvictions(reason, 1)
+	if c.onEvict != nil {
+		c.evictions = append(c.evictions, eviction[K, V]{key: key, value: value, reason: reason})
+	}
+}
+
+// recordPut records addition of the entry, reporting the old value if the
+// entry existed.
+func (c *Cache[K, V]) recordPut(key K, old V, exists bool) {
+	c.stats.recordPut(exists)
+	if exists {
+		c.recordEviction(key, old, EvictReplaced)
+	}
+}
+
+// resize runs fn under the write lock, reporting its evictions as resized.
+func (c *Cache[K, V]) resize(fn func() error) error {
+	c.lock.Lock()
+	defer c.unlock()
+	c.evictReason = EvictResized
+	defer func() {
+		c.evictReason = EvictCapacity
+	}()
+	return fn()
+}
+
+// unlock releases the write lock and notifies evictions recorded under it.
+func (c *Cache[K, V]) unlock() {
+	evictions, callback := c.evictions, c.onEvict
+	c.evictions = nil
+	c.lock.Unlock()
+	notify(callback, evictions)
+}
+
+// unlockAsync releases the write lock like unlock, but notifies evictions in
+// a new goroutine. Background goroutines unlock by it, since Close called by
+// a callback waits for them to exit.
+func (c *Cache[K, V]) unlockAsync() {
+	evictions, callback := c.evictions, c.onEvict
+	c.evictions = nil
+	c.lock.Unlock()
+	if len(evictions) > 0 {
+		go notify(callback, evictions)
+	}
+}
+
+func notify[K comparable, V any](callback EvictionCallback[K, V], evictions []eviction[K, V]) {
+	for _, e := range evictions {
+		callback(e.key, e.value, e.reason)
+	}
+}
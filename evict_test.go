@@ -0,0 +1,215 @@
+package nucleus
+
+import (
+	"context"
+	"errors"
+	"github.com/SemihBKGR/nucleus/nucleustest"
+	"github.com/SemihBKGR/nucleus/tlru"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type evicted struct {
+	key    interface{}
+	value  interface{}
+	reason EvictReason
+}
+
+func recordEvictions(cache *Cache[interface{}, interface{}]) func() []evicted {
+	lock := sync.Mutex{}
+	evictions := make([]evicted, 0)
+	cache.OnEvict(func(key, value interface{}, reason EvictReason) {
+		lock.Lock()
+		defer lock.Unlock()
+		evictions = append(evictions, evicted{key, value, reason})
+	})
+	return func() []evicted {
+		lock.Lock()
+		defer lock.Unlock()
+		result := evictions
+		evictions = make([]evicted, 0)
+		return result
+	}
+}
+
+// waitEvictions returns the recorded evictions once there are n of them, or
+// after a second. Evictions by background goroutines are notified
+// asynchronously.
+func waitEvictions(evictions func() []evicted, n int) []evicted {
+	result := evictions()
+	deadline := time.Now().Add(time.Second)
+	for len(result) < n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		result = append(result, evictions()...)
+	}
+	return result
+}
+
+func TestCache_OnEvict(t *testing.T) {
+	capacity := 3
+	cache, _ := NewLruCache(capacity)
+	evictions := recordEvictions(cache)
+	for i := 0; i < capacity+1; i++ {
+		cache.Add(i, strconv.Itoa(i))
+	}
+	if e := evictions(); len(e) != 1 || e[0] != (evicted{0, "0", EvictCapacity}) {
+		t.FailNow()
+	}
+	cache.Add(1, "one")
+	if e := evictions(); len(e) != 1 || e[0] != (evicted{1, "1", EvictReplaced}) {
+		t.FailNow()
+	}
+	cache.Set(1, "1")
+	if e := evictions(); len(e) != 1 || e[0] != (evicted{1, "one", EvictReplaced}) {
+		t.FailNow()
+	}
+	cache.Remove(2)
+	cache.Remove(2)
+	if e := evictions(); len(e) != 1 || e[0] != (evicted{2, "2", EvictRemoved}) {
+		t.FailNow()
+	}
+	cache.Add(2, "2")
+	_ = cache.SetCap(1)
+	if e := evictions(); len(e) != 2 || e[0].reason != EvictResized || e[1].reason != EvictResized {
+		t.FailNow()
+	}
+	_ = cache.SetCap(capacity)
+	cache.Add(3, "3")
+	cache.Clear()
+	if e := evictions(); len(e) != 2 || e[0].reason != EvictCleared || e[1].reason != EvictCleared {
+		t.FailNow()
+	}
+	cache.OnEvict(nil)
+	cache.Add(1, "1")
+	cache.Remove(1)
+	if e := evictions(); len(e) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCache_OnEvict2(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCacheWithSweep(capacity, 10*time.Millisecond, time.Hour)
+	defer cache.Close()
+	evictions := recordEvictions(cache)
+	cache.Add(1, "1")
+	_, _ = cache.AddWithTTL(2, "2", time.Hour)
+	time.Sleep(20 * time.Millisecond)
+	if cache.Contains(1) {
+		t.FailNow()
+	}
+	if e := evictions(); len(e) != 1 || e[0] != (evicted{1, "1", EvictExpired}) {
+		t.FailNow()
+	}
+	// entries expired by the daemon
+	cache, _ = NewTlruCacheWithSweep(capacity, 10*time.Millisecond, 10*time.Millisecond)
+	defer cache.Close()
+	evictions = recordEvictions(cache)
+	cache.Add(1, "1")
+	time.Sleep(50 * time.Millisecond)
+	if e := waitEvictions(evictions, 1); len(e) != 1 || e[0] != (evicted{1, "1", EvictExpired}) {
+		t.FailNow()
+	}
+}
+
+func TestCache_OnEvict3(t *testing.T) {
+	capacity := 2
+	cache, _ := NewLruCache(capacity)
+	// callbacks run outside of the lock, so they can use the cache
+	lengths := make([]int, 0)
+	cache.OnEvict(func(key, value interface{}, reason EvictReason) {
+		lengths = append(lengths, cache.Len())
+	})
+	cache.Add(1, "1")
+	cache.Add(2, "2")
+	cache.Add(3, "3")
+	if len(lengths) != 1 || lengths[0] != capacity {
+		t.FailNow()
+	}
+}
+
+func TestCache_OnEvict4(t *testing.T) {
+	capacity := 10
+	clock := nucleustest.NewFakeClock(time.Now())
+	cache, _ := NewTlruCache(capacity, time.Minute, WithClock(clock))
+	// callbacks of daemon sweeps can close the cache stopping the daemon
+	closed := make(chan struct{})
+	cache.OnEvict(func(key, value interface{}, reason EvictReason) {
+		cache.Close()
+		close(closed)
+	})
+	cache.Add(1, "1")
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.FailNow()
+	}
+	if cache.policy.(*tlru.Tlru[interface{}, interface{}]).DaemonStarted() {
+		t.FailNow()
+	}
+}
+
+func TestCache_OnEvict5(t *testing.T) {
+	capacity := 10
+	clock := nucleustest.NewFakeClock(time.Now())
+	cache, _ := NewTlruCacheWithSweep(capacity, time.Hour, 0, WithClock(clock))
+	loader := func(ctx context.Context, key interface{}) (interface{}, error) {
+		return "new", nil
+	}
+	_ = cache.SetRefresh(time.Minute, 1, loader)
+	// callbacks of refresh reloads can close the cache stopping the workers
+	closed := make(chan struct{})
+	cache.OnEvict(func(key, value interface{}, reason EvictReason) {
+		if reason == EvictReplaced && value == "old" {
+			cache.Close()
+			close(closed)
+		}
+	})
+	cache.Add(1, "old")
+	clock.Advance(time.Minute)
+	cache.Get(1)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.FailNow()
+	}
+	if !errors.Is(cache.SetRefresh(time.Minute, 1, loader), ErrClosed) {
+		t.FailNow()
+	}
+}
+
+func TestShardedCache_OnEvict(t *testing.T) {
+	capacity := 4
+	sharded, _ := NewShardedCache(2, capacity, NewLruCache)
+	count := 0
+	sharded.OnEvict(func(key, value interface{}, reason EvictReason) {
+		count++
+	})
+	for i := 0; i < capacity*2; i++ {
+		sharded.Add(i, i)
+	}
+	if count != capacity*2-sharded.Len() {
+		t.FailNow()
+	}
+}
+
+func TestEvictReason_String(t *testing.T) {
+	reasons := map[EvictReason]string{
+		EvictCapacity:   "capacity",
+		EvictExpired:    "expired",
+		EvictRemoved:    "removed",
+		EvictCleared:    "cleared",
+		EvictReplaced:   "replaced",
+		EvictResized:    "resized",
+		EvictReason(-1): "unknown",
+	}
+	for reason, name := range reasons {
+		if reason.String() != name {
+			t.FailNow()
+		}
+	}
+}
@@ -1,132 +1,199 @@
 package fifo
 
 import (
-	"container/list"
-	"errors"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+	"github.com/SemihBKGR/nucleus/policyutil"
 )
 
 // Fifo First in first out cache policy
-type Fifo struct {
+type Fifo[K comparable, V any] struct {
 	capacity     int
-	elementMap   map[interface{}]*list.Element
-	evictionList *list.List
+	evictionList *policyutil.List[K, V]
+	evictHandler func(key K, value V)
+	weigher      func(key K, value V) int64
+	maxCost      int64
 }
 
-type entry struct {
-	key   interface{}
-	value interface{}
-}
-
-// NewFifo returns new lru
-func NewFifo(capacity int) (*Fifo, error) {
+// New returns new typed fifo
+func New[K comparable, V any](capacity int) (*Fifo[K, V], error) {
 	if capacity <= 0 {
-		return nil, errors.New("capacity must be positive value")
+		return nil, errs.ErrNonPositiveCapacity
 	}
-	fifo := &Fifo{
+	fifo := &Fifo[K, V]{
 		capacity:     capacity,
-		elementMap:   make(map[interface{}]*list.Element),
-		evictionList: list.New(),
+		evictionList: policyutil.NewList[K, V](),
 	}
 	return fifo, nil
 }
 
+// NewFifo returns new fifo
+func NewFifo(capacity int) (*Fifo[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity)
+}
+
 // Add adds entry in cache
-func (f *Fifo) Add(key, value interface{}) (eviction bool) {
-	if element, ok := f.elementMap[key]; ok {
-		f.evictionList.MoveToFront(element)
-		element.Value.(*entry).value = value
-		return false
-	}
-	eviction = len(f.elementMap) >= f.capacity
-	if eviction {
-		f.evict()
+// Cost of the entry is given by the weigher, or 1 if there is none.
+func (f *Fifo[K, V]) Add(key K, value V) (eviction bool) {
+	return f.AddWithCost(key, value, f.weigh(key, value))
+}
+
+// AddWithCost adds entry in cache with given cost.
+// Entries are evicted until both length and total cost fit, an entry whose
+// cost exceeds max cost is evicted right away.
+func (f *Fifo[K, V]) AddWithCost(key K, value V, cost int64) (eviction bool) {
+	cost = max(cost, 0)
+	f.Remove(key)
+	if f.maxCost > 0 && cost > f.maxCost {
+		if f.evictHandler != nil {
+			f.evictHandler(key, value)
+		}
+		return true
 	}
-	entry := &entry{
-		key:   key,
-		value: value,
+	for (f.evictionList.Len() >= f.capacity || f.maxCost > 0 && f.evictionList.Cost()+cost > f.maxCost) && f.evict() {
+		eviction = true
 	}
-	element := f.evictionList.PushFront(entry)
-	f.elementMap[key] = element
+	f.evictionList.PushFront(key, value, cost)
 	return
 }
 
 // Get returns value of cached entry.
-func (f *Fifo) Get(key interface{}, _ bool) (value interface{}, ok bool) {
-	if element, ok := f.elementMap[key]; ok {
-		return element.Value.(*entry).value, true
+func (f *Fifo[K, V]) Get(key K, _ bool) (value V, ok bool) {
+	if entry, ok := f.evictionList.Get(key); ok {
+		return entry.Value, true
 	}
-	return nil, false
+	return
 }
 
 // Remove removes cache entry.
-func (f *Fifo) Remove(key interface{}) bool {
-	element, ok := f.elementMap[key]
+func (f *Fifo[K, V]) Remove(key K) bool {
+	entry, ok := f.evictionList.Get(key)
 	if !ok {
 		return false
 	}
-	delete(f.elementMap, key)
-	f.evictionList.Remove(element)
+	f.evictionList.Remove(entry)
 	return true
 }
 
-func (f *Fifo) evict() bool {
-	element := f.evictionList.Back()
-	if element != nil {
-		return f.Remove(element.Value.(*entry).key)
+// OnEvict sets handler called with entries evicted due to capacity.
+func (f *Fifo[K, V]) OnEvict(handler func(key K, value V)) {
+	f.evictHandler = handler
+}
+
+func (f *Fifo[K, V]) evict() bool {
+	entry := f.evictionList.Back()
+	if entry == nil {
+		return false
+	}
+	f.evictionList.Remove(entry)
+	if f.evictHandler != nil {
+		f.evictHandler(entry.Key, entry.Value)
 	}
-	return false
+	return true
 }
 
 // Clear removes all entries in the cache.
-func (f *Fifo) Clear() int {
+func (f *Fifo[K, V]) Clear() int {
 	length := f.Len()
-	for key := range f.elementMap {
-		delete(f.elementMap, key)
-	}
-	f.evictionList.Init()
+	f.evictionList.Clear()
 	return length
 }
 
 // Len returns length of the cache.
-func (f *Fifo) Len() int {
+func (f *Fifo[K, V]) Len() int {
 	return f.evictionList.Len()
 }
 
 // Cap returns capacity of the cache.
-func (f *Fifo) Cap() int {
+func (f *Fifo[K, V]) Cap() int {
 	return f.capacity
 }
 
+// Cost returns total cost of the entries in the cache.
+func (f *Fifo[K, V]) Cost() int64 {
+	return f.evictionList.Cost()
+}
+
+// MaxCost returns max total cost of the cache, zero if unbounded.
+func (f *Fifo[K, V]) MaxCost() int64 {
+	return f.maxCost
+}
+
+// SetMaxCost set max total cost of the cache, zero for unbounded.
+// Entries are evicted until the total cost fits the new max cost.
+// Returns error if maxCost is negative value.
+func (f *Fifo[K, V]) SetMaxCost(maxCost int64) error {
+	if maxCost < 0 {
+		return errs.ErrNegativeMaxCost
+	}
+	f.maxCost = maxCost
+	for maxCost > 0 && f.evictionList.Cost() > maxCost {
+		f.evict()
+	}
+	return nil
+}
+
+// SetWeigher sets function giving cost of the entries added by Add.
+// Costs of the entries already in the cache are not changed.
+func (f *Fifo[K, V]) SetWeigher(weigher func(key K, value V) int64) {
+	f.weigher = weigher
+}
+
+func (f *Fifo[K, V]) weigh(key K, value V) int64 {
+	if f.weigher == nil {
+		return 1
+	}
+	return f.weigher(key, value)
+}
+
 // SetCap set capacity of the cache.
+// Entries are evicted until the length fits the new capacity.
 // Returns error unless newCap is negative value.
-func (f *Fifo) SetCap(newCapacity int) error {
+func (f *Fifo[K, V]) SetCap(newCapacity int) error {
 	if newCapacity <= 0 {
-		return errors.New("capacity must be positive value")
+		return errs.ErrNonPositiveCapacity
 	}
-	if f.capacity < newCapacity {
-		diff := newCapacity - f.capacity
-		for i := 0; i < diff; i++ {
-			f.evict()
-		}
+	for f.evictionList.Len() > newCapacity {
+		f.evict()
 	}
 	f.capacity = newCapacity
 	return nil
 }
 
 // Keys returns a slice of entry keys in the cache.
-func (f *Fifo) Keys() []interface{} {
-	keys := make([]interface{}, 0, len(f.elementMap))
-	for k := range f.elementMap {
-		keys = append(keys, k)
-	}
-	return keys
+func (f *Fifo[K, V]) Keys() []K {
+	return f.evictionList.Keys()
 }
 
 // Values returns a slice of entry values in the cache.
-func (f *Fifo) Values() []interface{} {
-	values := make([]interface{}, 0, len(f.elementMap))
-	for _, v := range f.elementMap {
-		values = append(values, v.Value.(*entry).value)
+func (f *Fifo[K, V]) Values() []V {
+	return f.evictionList.Values()
+}
+
+// Range calls fn for entries from the least recently added or used one to
+// the most recent one, until fn returns false.
+func (f *Fifo[K, V]) Range(fn func(key K, value V) bool) {
+	for entry := f.evictionList.Back(); entry != nil; entry = entry.Prev() {
+		if !fn(entry.Key, entry.Value) {
+			return
+		}
+	}
+}
+
+// Oldest returns the least recently added or used entry.
+// Returns false if the cache is empty.
+func (f *Fifo[K, V]) Oldest() (key K, value V, ok bool) {
+	return entryOf[K, V](f.evictionList.Back())
+}
+
+// Newest returns the most recently added or used entry.
+// Returns false if the cache is empty.
+func (f *Fifo[K, V]) Newest() (key K, value V, ok bool) {
+	return entryOf[K, V](f.evictionList.Front())
+}
+
+func entryOf[K comparable, V any](entry *policyutil.Entry[K, V]) (key K, value V, ok bool) {
+	if entry == nil {
+		return
 	}
-	return values
+	return entry.Key, entry.Value, true
 }
@@ -3,6 +3,7 @@ package fifo
 import (
 	"math"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -30,6 +31,27 @@ func TestNewFifo(t *testing.T) {
 	}
 }
 
+func TestNew(t *testing.T) {
+	fifo, err := New[string, int](1)
+	if fifo == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	fifo.Add("1", 1)
+	if value, ok := fifo.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	fifo, err = New[string, int](0)
+	if fifo != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
 func TestFifo_Add(t *testing.T) {
 	capacity := 10
 	fifo, _ := NewFifo(capacity)
@@ -223,3 +245,132 @@ func containsAll(s []interface{}, es ...interface{}) bool {
 	}
 	return true
 }
+
+func TestFifo_OnEvict(t *testing.T) {
+	capacity := 10
+	fifo, _ := NewFifo(capacity)
+	evicted := make([]interface{}, 0)
+	fifo.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		fifo.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	fifo.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || fifo.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != 0 {
+		t.FailNow()
+	}
+	if _, ok := fifo.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	fifo.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || fifo.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
+
+func TestFifo_AddWithCost(t *testing.T) {
+	capacity := 10
+	fifo, _ := NewFifo(capacity)
+	_ = fifo.SetMaxCost(10)
+	fifo.AddWithCost(1, "1", 4)
+	fifo.AddWithCost(2, "2", 4)
+	if fifo.Cost() != 8 || fifo.MaxCost() != 10 {
+		t.FailNow()
+	}
+	// 3 does not fit until an entry is evicted
+	if !fifo.AddWithCost(3, "3", 4) || fifo.Cost() != 8 || fifo.Len() != 2 {
+		t.FailNow()
+	}
+	if _, ok := fifo.Get(1, false); ok {
+		t.FailNow()
+	}
+	// updating replaces the cost
+	if fifo.AddWithCost(3, "3", 1) || fifo.Cost() != 5 {
+		t.FailNow()
+	}
+	// entries costlier than max cost are evicted right away
+	evicted := make([]interface{}, 0)
+	fifo.OnEvict(func(key, value interface{}) {
+		evicted = append(evicted, key)
+	})
+	if !fifo.AddWithCost(4, "4", 11) || fifo.Cost() != 5 || len(evicted) != 1 || evicted[0] != 4 {
+		t.FailNow()
+	}
+	if _, ok := fifo.Get(4, false); ok {
+		t.FailNow()
+	}
+}
+
+func TestFifo_SetMaxCost(t *testing.T) {
+	capacity := 10
+	fifo, _ := NewFifo(capacity)
+	fifo.SetWeigher(func(key, value interface{}) int64 {
+		return int64(len(value.(string)))
+	})
+	for i := 0; i < capacity; i++ {
+		fifo.Add(i, strings.Repeat("a", i))
+	}
+	if fifo.Cost() != 45 || fifo.MaxCost() != 0 {
+		t.FailNow()
+	}
+	if fifo.SetMaxCost(-1) == nil {
+		t.FailNow()
+	}
+	if fifo.SetMaxCost(20) != nil || fifo.Cost() > 20 || fifo.MaxCost() != 20 {
+		t.FailNow()
+	}
+	fifo.Clear()
+	if fifo.Cost() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestFifo_Range(t *testing.T) {
+	capacity := 5
+	fifo, _ := NewFifo(capacity)
+	for i := 0; i < capacity; i++ {
+		fifo.Add(i, i)
+	}
+	fifo.Get(0, true)
+	keys := make([]interface{}, 0)
+	fifo.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+	if len(keys) != 3 {
+		t.FailNow()
+	}
+	for i, key := range keys {
+		if key != i {
+			t.FailNow()
+		}
+	}
+}
+
+func TestFifo_Oldest(t *testing.T) {
+	capacity := 5
+	fifo, _ := NewFifo(capacity)
+	if _, _, ok := fifo.Oldest(); ok {
+		t.FailNow()
+	}
+	for i := 0; i < capacity; i++ {
+		fifo.Add(i, i)
+	}
+	fifo.Get(0, true)
+	if key, value, ok := fifo.Oldest(); !ok || key != 0 || value != 0 {
+		t.FailNow()
+	}
+	if key, value, ok := fifo.Newest(); !ok || key != capacity-1 || value != capacity-1 {
+		t.FailNow()
+	}
+}
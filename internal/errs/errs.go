@@ -0,0 +1,28 @@
+// Package errs holds errors shared by the policies and the nucleus package,
+// which exports them, so that errors of both match with errors.Is.
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidCapacity is returned for capacities which are not positive
+	// value.
+	ErrInvalidCapacity = errors.New("invalid capacity")
+	// ErrInvalidTTL is returned for ttl and durations of expiration which
+	// are out of range.
+	ErrInvalidTTL = errors.New("invalid ttl")
+	// ErrInvalidCost is returned for negative costs and max costs.
+	ErrInvalidCost = errors.New("invalid cost")
+	// ErrInvalidArgument is returned for other arguments which are out of
+	// range, such as ratios of the segments.
+	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrNonPositiveCapacity is returned by policies given capacity less
+	// than one.
+	ErrNonPositiveCapacity = fmt.Errorf("%w: must be positive value", ErrInvalidCapacity)
+	// ErrNegativeMaxCost is returned by policies given negative max cost.
+	ErrNegativeMaxCost = fmt.Errorf("%w: max cost must not be negative value", ErrInvalidCost)
+)
@@ -0,0 +1,114 @@
+// Package keyhash hashes comparable keys for the sketch of tinylfu and the
+// shards of nucleus. Unlike maphash.Comparable, it builds with go versions
+// before 1.24.
+package keyhash
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// Hasher hashes keys with a random seed. Equal keys have equal hashes.
+type Hasher[K comparable] struct {
+	seed maphash.Seed
+}
+
+// New returns new hasher with a random seed.
+func New[K comparable]() Hasher[K] {
+	return Hasher[K]{seed: maphash.MakeSeed()}
+}
+
+// Sum returns hash of the key. Strings, numbers and bools are hashed
+// directly, other keys are walked by reflection.
+func (h Hasher[K]) Sum(key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(h.seed, k)
+	case int:
+		return h.sum64(uint64(k))
+	case int64:
+		return h.sum64(uint64(k))
+	case int32:
+		return h.sum64(uint64(k))
+	case uint:
+		return h.sum64(uint64(k))
+	case uint64:
+		return h.sum64(k)
+	case uint32:
+		return h.sum64(uint64(k))
+	case bool:
+		if k {
+			return h.sum64(1)
+		}
+		return h.sum64(0)
+	}
+	var hash maphash.Hash
+	hash.SetSeed(h.seed)
+	write(&hash, reflect.ValueOf(&key).Elem())
+	return hash.Sum64()
+}
+
+func (h Hasher[K]) sum64(v uint64) uint64 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return maphash.Bytes(h.seed, b[:])
+}
+
+// write writes the value in the hash so that equal values write the same
+// bytes. Pointers and channels are written by address.
+func write(hash *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint64(hash, 1)
+		} else {
+			writeUint64(hash, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(hash, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(hash, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(hash, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(hash, real(c))
+		writeFloat(hash, imag(c))
+	case reflect.String:
+		writeUint64(hash, uint64(v.Len()))
+		_, _ = hash.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(hash, uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			writeUint64(hash, 0)
+			return
+		}
+		write(hash, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			write(hash, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			write(hash, v.Field(i))
+		}
+	}
+}
+
+// writeFloat writes the float so that zero and negative zero, which are
+// equal, write the same bytes.
+func writeFloat(hash *maphash.Hash, f float64) {
+	if f == 0 {
+		f = 0
+	}
+	writeUint64(hash, math.Float64bits(f))
+}
+
+func writeUint64(hash *maphash.Hash, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	_, _ = hash.Write(b[:])
+}
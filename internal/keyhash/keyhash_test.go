@@ -0,0 +1,57 @@
+package keyhash
+
+import (
+	"math"
+	"testing"
+)
+
+type key struct {
+	name  string
+	id    int
+	ratio float64
+	ptr   *int
+	any   interface{}
+}
+
+func TestHasher_Sum(t *testing.T) {
+	hasher := New[string]()
+	if hasher.Sum("a") != hasher.Sum("a") || hasher.Sum("a") == hasher.Sum("b") {
+		t.FailNow()
+	}
+	intHasher := New[int]()
+	if intHasher.Sum(1) != intHasher.Sum(1) || intHasher.Sum(1) == intHasher.Sum(2) {
+		t.FailNow()
+	}
+}
+
+func TestHasher_Sum2(t *testing.T) {
+	hasher := New[key]()
+	value := 1
+	k := key{name: "a", id: 1, ratio: math.Copysign(0, -1), ptr: &value, any: 2}
+	sum := hasher.Sum(k)
+	value = 2
+	// zero and negative zero are equal, pointers are hashed by address
+	if hasher.Sum(key{name: "a", id: 1, ratio: 0, ptr: &value, any: 2}) != sum {
+		t.FailNow()
+	}
+	if hasher.Sum(key{name: "a", id: 1, ptr: &value, any: 3}) == sum {
+		t.FailNow()
+	}
+	other := 2
+	if hasher.Sum(key{name: "a", id: 1, ptr: &other, any: 2}) == sum {
+		t.FailNow()
+	}
+}
+
+func TestHasher_Sum3(t *testing.T) {
+	hasher := New[interface{}]()
+	if hasher.Sum(nil) != hasher.Sum(nil) {
+		t.FailNow()
+	}
+	if hasher.Sum(1) != hasher.Sum(1) || hasher.Sum("1") != hasher.Sum("1") {
+		t.FailNow()
+	}
+	if hasher.Sum([2]int{1, 2}) != hasher.Sum([2]int{1, 2}) || hasher.Sum([2]int{1, 2}) == hasher.Sum([2]int{2, 1}) {
+		t.FailNow()
+	}
+}
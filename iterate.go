@@ -0,0 +1,67 @@
+package nucleus
+
+// Entry is a key value pair in the cache.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Range calls fn for entries until fn returns false.
+// Entries of OrderedPolicy policies are walked from the least recently
+// added or used one to the most recent one, others in no particular order.
+// Recency of the entries is not changed. The cache is locked during the
+// walk, so fn must not call methods of the cache.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.readLock()
+	defer c.readUnlock()
+	c.rangeEntries(fn)
+}
+
+// Entries returns a slice of entries in the order of Range.
+func (c *Cache[K, V]) Entries() []Entry[K, V] {
+	c.readLock()
+	defer c.readUnlock()
+	entries := make([]Entry[K, V], 0, c.policy.Len())
+	c.rangeEntries(func(key K, value V) bool {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+		return true
+	})
+	return entries
+}
+
+// Oldest returns the least recently added or used entry.
+// Returns false if the cache is empty or policy is not an OrderedPolicy.
+func (c *Cache[K, V]) Oldest() (key K, value V, ok bool) {
+	policy, ordered := c.policy.(OrderedPolicy[K, V])
+	if !ordered {
+		return
+	}
+	c.readLock()
+	defer c.readUnlock()
+	return policy.Oldest()
+}
+
+// Newest returns the most recently added or used entry.
+// Returns false if the cache is empty or policy is not an OrderedPolicy.
+func (c *Cache[K, V]) Newest() (key K, value V, ok bool) {
+	policy, ordered := c.policy.(OrderedPolicy[K, V])
+	if !ordered {
+		return
+	}
+	c.readLock()
+	defer c.readUnlock()
+	return policy.Newest()
+}
+
+// rangeEntries walks the entries, the cache must be locked by the caller.
+func (c *Cache[K, V]) rangeEntries(fn func(key K, value V) bool) {
+	if policy, ok := c.policy.(OrderedPolicy[K, V]); ok {
+		policy.Range(fn)
+		return
+	}
+	for _, key := range c.policy.Keys() {
+		if value, ok := c.policy.Get(key, false); ok && !fn(key, value) {
+			return
+		}
+	}
+}
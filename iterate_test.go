@@ -0,0 +1,81 @@
+package nucleus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_Range(t *testing.T) {
+	capacity := 5
+	cache, _ := NewLruCache(capacity)
+	for i := 0; i < capacity; i++ {
+		cache.Add(i, i*10)
+	}
+	cache.Get(1)
+	keys := make([]interface{}, 0)
+	cache.Range(func(key, value interface{}) bool {
+		if value != key.(int)*10 {
+			t.Error("unexpected value")
+		}
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	if len(keys) != 2 || keys[0] != 0 || keys[1] != 2 {
+		t.FailNow()
+	}
+	if key, value, ok := cache.Oldest(); !ok || key != 0 || value != 0 {
+		t.FailNow()
+	}
+	if key, value, ok := cache.Newest(); !ok || key != 1 || value != 10 {
+		t.FailNow()
+	}
+	// walking does not change recency
+	if key, _, _ := cache.Oldest(); key != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCache_Entries(t *testing.T) {
+	capacity := 5
+	cache, _ := NewFifoCache(capacity)
+	if len(cache.Entries()) != 0 {
+		t.FailNow()
+	}
+	if _, _, ok := cache.Oldest(); ok {
+		t.FailNow()
+	}
+	for i := 0; i < capacity*2; i++ {
+		cache.Add(i, i)
+	}
+	entries := cache.Entries()
+	if len(entries) != capacity {
+		t.FailNow()
+	}
+	for i, entry := range entries {
+		if entry != (Entry[interface{}, interface{}]{Key: i + capacity, Value: i + capacity}) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestCache_Entries2(t *testing.T) {
+	capacity := 5
+	cache, _ := NewTlruCacheWithSweep(capacity, time.Hour, 0)
+	cache.Add(1, 1)
+	_, _ = cache.AddWithTTL(2, 2, time.Millisecond)
+	cache.Add(3, 3)
+	time.Sleep(2 * time.Millisecond)
+	entries := cache.Entries()
+	if len(entries) != 2 || entries[0].Key != 1 || entries[1].Key != 3 {
+		t.FailNow()
+	}
+	arcCache, _ := NewArcCache(capacity)
+	arcCache.Add(1, 1)
+	arcCache.Add(2, 2)
+	if len(arcCache.Entries()) != 2 {
+		t.FailNow()
+	}
+	if _, _, ok := arcCache.Newest(); ok {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,198 @@
+package lfu
+
+import (
+	"container/list"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+)
+
+// Lfu Least frequently used cache policy
+type Lfu[K comparable, V any] struct {
+	capacity      int
+	elementMap    map[K]*list.Element
+	frequencyList *list.List
+	evictHandler  func(key K, value V)
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	frequency *list.Element
+}
+
+type frequencyNode struct {
+	count   int
+	entries *list.List
+}
+
+// New returns new typed lfu
+func New[K comparable, V any](capacity int) (*Lfu[K, V], error) {
+	if capacity <= 0 {
+		return nil, errs.ErrNonPositiveCapacity
+	}
+	lfu := &Lfu[K, V]{
+		capacity:      capacity,
+		elementMap:    make(map[K]*list.Element),
+		frequencyList: list.New(),
+	}
+	return lfu, nil
+}
+
+// NewLfu returns new lfu
+func NewLfu(capacity int) (*Lfu[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity)
+}
+
+// Add adds entry in cache
+func (l *Lfu[K, V]) Add(key K, value V) (eviction bool) {
+	if element, ok := l.elementMap[key]; ok {
+		element.Value.(*entry[K, V]).value = value
+		l.increment(element)
+		return false
+	}
+	eviction = len(l.elementMap) >= l.capacity
+	if eviction {
+		l.evict()
+	}
+	frequency := l.frequencyList.Front()
+	if frequency == nil || frequency.Value.(*frequencyNode).count != 1 {
+		frequency = l.frequencyList.PushFront(&frequencyNode{
+			count:   1,
+			entries: list.New(),
+		})
+	}
+	entry := &entry[K, V]{
+		key:       key,
+		value:     value,
+		frequency: frequency,
+	}
+	element := frequency.Value.(*frequencyNode).entries.PushFront(entry)
+	l.elementMap[key] = element
+	return
+}
+
+// Get returns value of cached entry.
+func (l *Lfu[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if element, ok := l.elementMap[key]; ok {
+		if trigger {
+			element = l.increment(element)
+		}
+		return element.Value.(*entry[K, V]).value, true
+	}
+	return
+}
+
+// increment moves the entry to the bucket of the next frequency,
+// creating the bucket when it does not exist yet.
+func (l *Lfu[K, V]) increment(element *list.Element) *list.Element {
+	entry := element.Value.(*entry[K, V])
+	current := entry.frequency
+	currentNode := current.Value.(*frequencyNode)
+	next := current.Next()
+	if next == nil || next.Value.(*frequencyNode).count != currentNode.count+1 {
+		next = l.frequencyList.InsertAfter(&frequencyNode{
+			count:   currentNode.count + 1,
+			entries: list.New(),
+		}, current)
+	}
+	currentNode.entries.Remove(element)
+	if currentNode.entries.Len() == 0 {
+		l.frequencyList.Remove(current)
+	}
+	entry.frequency = next
+	element = next.Value.(*frequencyNode).entries.PushFront(entry)
+	l.elementMap[entry.key] = element
+	return element
+}
+
+// Remove removes cache entry.
+func (l *Lfu[K, V]) Remove(key K) bool {
+	element, ok := l.elementMap[key]
+	if !ok {
+		return false
+	}
+	delete(l.elementMap, key)
+	frequency := element.Value.(*entry[K, V]).frequency
+	entries := frequency.Value.(*frequencyNode).entries
+	entries.Remove(element)
+	if entries.Len() == 0 {
+		l.frequencyList.Remove(frequency)
+	}
+	return true
+}
+
+// OnEvict sets handler called with entries evicted due to capacity.
+func (l *Lfu[K, V]) OnEvict(handler func(key K, value V)) {
+	l.evictHandler = handler
+}
+
+func (l *Lfu[K, V]) evict() bool {
+	frequency := l.frequencyList.Front()
+	if frequency == nil {
+		return false
+	}
+	entry := frequency.Value.(*frequencyNode).entries.Back().Value.(*entry[K, V])
+	l.Remove(entry.key)
+	if l.evictHandler != nil {
+		l.evictHandler(entry.key, entry.value)
+	}
+	return true
+}
+
+// Clear removes all entries in the cache.
+func (l *Lfu[K, V]) Clear() int {
+	length := l.Len()
+	for key := range l.elementMap {
+		delete(l.elementMap, key)
+	}
+	l.frequencyList.Init()
+	return length
+}
+
+// Len returns length of the cache.
+func (l *Lfu[K, V]) Len() int {
+	return len(l.elementMap)
+}
+
+// Cap returns capacity of the cache.
+func (l *Lfu[K, V]) Cap() int {
+	return l.capacity
+}
+
+// SetCap set capacity of the cache.
+// Returns error unless newCap is negative value.
+func (l *Lfu[K, V]) SetCap(newCapacity int) error {
+	if newCapacity <= 0 {
+		return errs.ErrNonPositiveCapacity
+	}
+	for l.Len() > newCapacity {
+		l.evict()
+	}
+	l.capacity = newCapacity
+	return nil
+}
+
+// Keys returns a slice of entry keys in the cache.
+func (l *Lfu[K, V]) Keys() []K {
+	keys := make([]K, 0, len(l.elementMap))
+	for k := range l.elementMap {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns a slice of entry values in the cache.
+func (l *Lfu[K, V]) Values() []V {
+	values := make([]V, 0, len(l.elementMap))
+	for _, v := range l.elementMap {
+		values = append(values, v.Value.(*entry[K, V]).value)
+	}
+	return values
+}
+
+// Frequency returns access frequency of cached entry.
+func (l *Lfu[K, V]) Frequency(key K) int {
+	if element, ok := l.elementMap[key]; ok {
+		return element.Value.(*entry[K, V]).frequency.Value.(*frequencyNode).count
+	}
+	return 0
+}
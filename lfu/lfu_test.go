@@ -0,0 +1,311 @@
+package lfu
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNewLfu(t *testing.T) {
+	lfu, err := NewLfu(1)
+	if lfu == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	lfu, err = NewLfu(0)
+	if lfu != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	lfu, err = NewLfu(-1)
+	if lfu != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNew(t *testing.T) {
+	lfu, err := New[string, int](1)
+	if lfu == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	lfu.Add("1", 1)
+	if value, ok := lfu.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	lfu, err = New[string, int](0)
+	if lfu != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestLfu_Add(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	for i := 0; i < capacity*2; i++ {
+		lfu.Add(i, strconv.Itoa(i))
+	}
+}
+
+func TestLfu_Add2(t *testing.T) {
+	capacity := 3
+	lfu, _ := NewLfu(capacity)
+	// 1 -> []
+	lfu.Add(1, nil)
+	// [1:1]
+	if !contains(lfu.Keys(), 1) {
+		t.FailNow()
+	}
+	// 2 -> [1:1]
+	lfu.Add(2, nil)
+	// [1:1,2:1]
+	if !containsAll(lfu.Keys(), 1, 2) {
+		t.FailNow()
+	}
+	// 1 -> [1:1,2:1]
+	lfu.Add(1, nil)
+	// [2:1,1:2]
+	if !containsAll(lfu.Keys(), 1, 2) {
+		t.FailNow()
+	}
+	// 3 -> [2:1,1:2]
+	lfu.Add(3, nil)
+	// [2:1,3:1,1:2]
+	if !containsAll(lfu.Keys(), 1, 2, 3) {
+		t.FailNow()
+	}
+	// 4 -> [2:1,3:1,1:2]
+	lfu.Add(4, nil)
+	// [3:1,4:1,1:2]
+	if !containsAll(lfu.Keys(), 1, 3, 4) {
+		t.FailNow()
+	}
+	// 3 -> [3:1,4:1,1:2]
+	lfu.Add(3, nil)
+	// [4:1,1:2,3:2]
+	if !containsAll(lfu.Keys(), 1, 3, 4) {
+		t.FailNow()
+	}
+	// 5 -> [4:1,1:2,3:2]
+	lfu.Add(5, nil)
+	// [5:1,1:2,3:2]
+	if !containsAll(lfu.Keys(), 1, 3, 5) {
+		t.FailNow()
+	}
+	// 6 -> [5:1,1:2,3:2]
+	lfu.Add(6, nil)
+	// [6:1,1:2,3:2]
+	if !containsAll(lfu.Keys(), 1, 3, 6) {
+		t.FailNow()
+	}
+}
+
+func TestLfu_Get(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	for i := 0; i < capacity; i++ {
+		lfu.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		value, ok := lfu.Get(i, true)
+		if !ok || value.(string) != strconv.Itoa(i) {
+			t.FailNow()
+		}
+	}
+	for i := capacity; i < capacity*2; i++ {
+		value, ok := lfu.Get(i, true)
+		if ok || value != nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestLfu_Remove(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	for i := 0; i < capacity; i++ {
+		lfu.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		ok := lfu.Remove(i)
+		if !ok {
+			t.FailNow()
+		}
+	}
+	for i := 0; i < capacity; i++ {
+		ok := lfu.Remove(i)
+		if ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestLfu_Clear(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	for i := 0; i < capacity; i++ {
+		lfu.Add(i, strconv.Itoa(i))
+	}
+	lfu.Clear()
+	if lfu.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestLfu_Cap(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	if lfu.Cap() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestLfu_Len(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	if lfu.Len() != 0 {
+		t.FailNow()
+	}
+	for i := 0; i < capacity*2; i++ {
+		lfu.Add(i, strconv.Itoa(i))
+		if lfu.Len() != int(math.Min(float64(i+1), float64(capacity))) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestLfu_SetCap(t *testing.T) {
+	capacity := 10
+	newCapacity := 20
+	lfu, _ := NewLfu(capacity)
+	err := lfu.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if lfu.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = 5
+	err = lfu.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if lfu.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = -1
+	err = lfu.SetCap(newCapacity)
+	if err == nil {
+		t.FailNow()
+	}
+	if lfu.Cap() == newCapacity {
+		t.FailNow()
+	}
+}
+
+func TestLfu_Keys(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	for i := 0; i < capacity*2; i++ {
+		lfu.Add(i, strconv.Itoa(i))
+		keys := lfu.Keys()
+		if !contains(keys, i) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestLfu_Values(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	for i := 0; i < capacity*2; i++ {
+		lfu.Add(i, strconv.Itoa(i))
+		values := lfu.Values()
+		if !contains(values, strconv.Itoa(i)) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestLfu_Frequency(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	if lfu.Frequency(1) != 0 {
+		t.FailNow()
+	}
+	lfu.Add(1, nil)
+	if lfu.Frequency(1) != 1 {
+		t.FailNow()
+	}
+	lfu.Get(1, true)
+	lfu.Add(1, nil)
+	if lfu.Frequency(1) != 3 {
+		t.FailNow()
+	}
+	lfu.Get(1, false)
+	if lfu.Frequency(1) != 3 {
+		t.FailNow()
+	}
+}
+
+func contains(s []interface{}, e interface{}) bool {
+	for _, c := range s {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(s []interface{}, es ...interface{}) bool {
+	for _, e := range es {
+		if !contains(s, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLfu_OnEvict(t *testing.T) {
+	capacity := 10
+	lfu, _ := NewLfu(capacity)
+	evicted := make([]interface{}, 0)
+	lfu.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		lfu.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	lfu.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || lfu.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != 0 {
+		t.FailNow()
+	}
+	if _, ok := lfu.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	lfu.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || lfu.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,167 @@
+package nucleus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LoaderFunc loads value of the key missing in the cache.
+type LoaderFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// loadGroup deduplicates concurrent loads of the same key and keeps
+// failed loads for the negative ttl.
+type loadGroup[K comparable, V any] struct {
+	lock        sync.Mutex
+	calls       map[K]*loadCall[V]
+	failures    map[K]loadFailure
+	negativeTTL time.Duration
+	clock       Clock
+}
+
+type loadCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loadFailure struct {
+	err       error
+	expiresAt time.Time
+}
+
+// GetOrLoad returns value of cached entry, or loads it by loader and adds it
+// in the cache on miss.
+// Concurrent loads of the same key are collapsed into a single loader call
+// which runs with the context of the first caller. Callers waiting for it
+// return early once their own context is done.
+// Errors of the loader are wrapped by *LoadError, they are not cached
+// unless a negative ttl is set by SetNegativeTTL.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[K, V]) (value V, err error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	g := &c.loadGroup
+	g.lock.Lock()
+	if failure, ok := g.failures[key]; ok {
+		if g.now().Before(failure.expiresAt) {
+			g.lock.Unlock()
+			return value, failure.err
+		}
+		delete(g.failures, key)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.lock.Unlock()
+		select {
+		case <-call.done:
+			return call.value, call.err
+		case <-ctx.Done():
+			return value, ctx.Err()
+		}
+	}
+	if g.calls == nil {
+		g.calls = make(map[K]*loadCall[V])
+	}
+	call := &loadCall[V]{done: make(chan struct{}), err: &LoadError{Key: key, Err: ErrLoaderPanicked}}
+	g.calls[key] = call
+	g.lock.Unlock()
+
+	defer func() {
+		g.lock.Lock()
+		delete(g.calls, key)
+		if call.err != nil && g.negativeTTL > 0 {
+			g.addFailure(key, call.err)
+		}
+		g.lock.Unlock()
+		close(call.done)
+	}()
+	start := time.Now()
+	value, err = loader(ctx, key)
+	c.stats.recordLoad(err, time.Since(start))
+	if err != nil {
+		call.err = &LoadError{Key: key, Err: err}
+		return value, call.err
+	}
+	call.value, call.err = value, nil
+	c.Add(key, value)
+	return value, nil
+}
+
+// SetNegativeTTL sets duration for which failed loads of GetOrLoad are
+// cached, so that the loader is not called again for the key until it
+// passes. Zero disables caching of errors.
+// Returns error if ttl is negative value.
+func (c *Cache[K, V]) SetNegativeTTL(ttl time.Duration) error {
+	if ttl < 0 {
+		return fmt.Errorf("%w: negative ttl must not be negative value", ErrInvalidTTL)
+	}
+	c.loadGroup.lock.Lock()
+	defer c.loadGroup.lock.Unlock()
+	c.loadGroup.negativeTTL = ttl
+	if ttl == 0 {
+		c.loadGroup.failures = nil
+	}
+	return nil
+}
+
+// GetOrLoad returns value of cached entry, or loads it by loader and adds it
+// in the shard of the key on miss.
+func (s *ShardedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[K, V]) (value V, err error) {
+	return s.shard(key).GetOrLoad(ctx, key, loader)
+}
+
+// SetNegativeTTL sets duration for which failed loads are cached in every
+// shard.
+// Returns error if ttl is negative value.
+func (s *ShardedCache[K, V]) SetNegativeTTL(ttl time.Duration) error {
+	for _, shard := range s.shards {
+		if err := shard.SetNegativeTTL(ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFailure caches the error of the key and drops expired ones.
+func (g *loadGroup[K, V]) addFailure(key K, err error) {
+	now := g.now()
+	if g.failures == nil {
+		g.failures = make(map[K]loadFailure)
+	}
+	for k, failure := range g.failures {
+		if !now.Before(failure.expiresAt) {
+			delete(g.failures, k)
+		}
+	}
+	g.failures[key] = loadFailure{err: err, expiresAt: now.Add(g.negativeTTL)}
+}
+
+// failed returns true if the error of the key is cached.
+func (g *loadGroup[K, V]) failed(key K) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	failure, ok := g.failures[key]
+	return ok && g.now().Before(failure.expiresAt)
+}
+
+// now returns current time of the clock, the system clock if not set.
+func (g *loadGroup[K, V]) now() time.Time {
+	if g.clock == nil {
+		return time.Now()
+	}
+	return g.clock.Now()
+}
+
+// forget drops cached errors of the keys, or all of them if no key given.
+func (g *loadGroup[K, V]) forget(keys ...K) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if len(keys) == 0 {
+		g.failures = nil
+		return
+	}
+	for _, key := range keys {
+		delete(g.failures, key)
+	}
+}
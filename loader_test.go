@@ -0,0 +1,169 @@
+package nucleus
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_GetOrLoad(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLru[int, string](capacity)
+	calls := 0
+	loader := func(ctx context.Context, key int) (string, error) {
+		calls++
+		return strconv.Itoa(key), nil
+	}
+	for i := 0; i < 2; i++ {
+		value, err := cache.GetOrLoad(context.Background(), 1, loader)
+		if err != nil || value != "1" {
+			t.FailNow()
+		}
+	}
+	if calls != 1 || !cache.Contains(1) {
+		t.FailNow()
+	}
+}
+
+func TestCache_GetOrLoad2(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLru[int, string](capacity)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key int) (string, error) {
+		calls.Add(1)
+		<-release
+		return strconv.Itoa(key), nil
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.GetOrLoad(context.Background(), 1, loader)
+			if err != nil || value != "1" {
+				t.Error("unexpected load result")
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.FailNow()
+	}
+}
+
+func TestCache_GetOrLoad3(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLru[int, string](capacity)
+	loadErr := errors.New("load failed")
+	calls := 0
+	loader := func(ctx context.Context, key int) (string, error) {
+		calls++
+		return "", loadErr
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cache.GetOrLoad(context.Background(), 1, loader); !errors.Is(err, loadErr) {
+			t.FailNow()
+		}
+	}
+	if calls != 2 || cache.Contains(1) {
+		t.FailNow()
+	}
+	// errors are cached for negative ttl
+	if cache.SetNegativeTTL(-time.Second) == nil {
+		t.FailNow()
+	}
+	_ = cache.SetNegativeTTL(20 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err := cache.GetOrLoad(context.Background(), 1, loader); !errors.Is(err, loadErr) {
+			t.FailNow()
+		}
+	}
+	if calls != 3 {
+		t.FailNow()
+	}
+	time.Sleep(30 * time.Millisecond)
+	_, _ = cache.GetOrLoad(context.Background(), 1, loader)
+	if calls != 4 {
+		t.FailNow()
+	}
+	// removing the key forgets the error
+	cache.Remove(1)
+	_, _ = cache.GetOrLoad(context.Background(), 1, loader)
+	if calls != 5 {
+		t.FailNow()
+	}
+}
+
+func TestCache_GetOrLoad4(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLru[int, string](capacity)
+	release := make(chan struct{})
+	defer close(release)
+	loader := func(ctx context.Context, key int) (string, error) {
+		<-release
+		return strconv.Itoa(key), nil
+	}
+	go func() {
+		_, _ = cache.GetOrLoad(context.Background(), 1, loader)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cache.GetOrLoad(ctx, 1, loader); err != context.DeadlineExceeded {
+		t.FailNow()
+	}
+}
+
+func TestCache_GetOrLoad5(t *testing.T) {
+	capacity := 10
+	loader := func(ctx context.Context, key interface{}) (interface{}, error) {
+		return key, nil
+	}
+	newCaches := map[string]func() (*Cache[interface{}, interface{}], error){
+		"lru":      func() (*Cache[interface{}, interface{}], error) { return NewLruCache(capacity) },
+		"mru":      func() (*Cache[interface{}, interface{}], error) { return NewMruCache(capacity) },
+		"fifo":     func() (*Cache[interface{}, interface{}], error) { return NewFifoCache(capacity) },
+		"tlru":     func() (*Cache[interface{}, interface{}], error) { return NewTlruCache(capacity, time.Second) },
+		"lfu":      func() (*Cache[interface{}, interface{}], error) { return NewLfuCache(capacity) },
+		"arc":      func() (*Cache[interface{}, interface{}], error) { return NewArcCache(capacity) },
+		"tinylfu":  func() (*Cache[interface{}, interface{}], error) { return NewTinyLfuCache(capacity) },
+		"twoq":     func() (*Cache[interface{}, interface{}], error) { return NewTwoQCache(capacity, 0.25, 0.5) },
+		"slru":     func() (*Cache[interface{}, interface{}], error) { return NewSlruCache(capacity, 0.8) },
+		"clock":    func() (*Cache[interface{}, interface{}], error) { return NewClockCache(capacity) },
+		"clockpro": func() (*Cache[interface{}, interface{}], error) { return NewClockProCache(capacity) },
+	}
+	for name, newCache := range newCaches {
+		cache, err := newCache()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		value, err := cache.GetOrLoad(context.Background(), 1, loader)
+		if err != nil || value != 1 {
+			t.Fatal(name)
+		}
+		if cached, ok := cache.Get(1); !ok || cached != 1 {
+			t.Fatal(name)
+		}
+		_ = cache.Close()
+	}
+}
+
+func TestShardedCache_GetOrLoad(t *testing.T) {
+	sharded, _ := NewSharded(4, 16, NewLru[int, string])
+	loader := func(ctx context.Context, key int) (string, error) {
+		return strconv.Itoa(key), nil
+	}
+	if value, err := sharded.GetOrLoad(context.Background(), 1, loader); err != nil || value != "1" {
+		t.FailNow()
+	}
+	if !sharded.Contains(1) || sharded.SetNegativeTTL(time.Second) != nil {
+		t.FailNow()
+	}
+}
@@ -1,135 +1,202 @@
 package lru
 
 import (
-	"container/list"
-	"errors"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+	"github.com/SemihBKGR/nucleus/policyutil"
 )
 
 // Lru Least recently used cache policy
-type Lru struct {
+type Lru[K comparable, V any] struct {
 	capacity     int
-	elementMap   map[interface{}]*list.Element
-	evictionList *list.List
+	evictionList *policyutil.List[K, V]
+	evictHandler func(key K, value V)
+	weigher      func(key K, value V) int64
+	maxCost      int64
 }
 
-type entry struct {
-	key   interface{}
-	value interface{}
-}
-
-// NewLru returns new lru
-func NewLru(capacity int) (*Lru, error) {
+// New returns new typed lru
+func New[K comparable, V any](capacity int) (*Lru[K, V], error) {
 	if capacity <= 0 {
-		return nil, errors.New("capacity must be positive value")
+		return nil, errs.ErrNonPositiveCapacity
 	}
-	lru := &Lru{
+	lru := &Lru[K, V]{
 		capacity:     capacity,
-		elementMap:   make(map[interface{}]*list.Element),
-		evictionList: list.New(),
+		evictionList: policyutil.NewList[K, V](),
 	}
 	return lru, nil
 }
 
+// NewLru returns new lru
+func NewLru(capacity int) (*Lru[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity)
+}
+
 // Add adds entry in cache
-func (l *Lru) Add(key, value interface{}) (eviction bool) {
-	if element, ok := l.elementMap[key]; ok {
-		l.evictionList.MoveToFront(element)
-		element.Value.(*entry).value = value
-		return false
-	}
-	eviction = len(l.elementMap) >= l.capacity
-	if eviction {
-		l.evict()
+// Cost of the entry is given by the weigher, or 1 if there is none.
+func (l *Lru[K, V]) Add(key K, value V) (eviction bool) {
+	return l.AddWithCost(key, value, l.weigh(key, value))
+}
+
+// AddWithCost adds entry in cache with given cost.
+// Entries are evicted until both length and total cost fit, an entry whose
+// cost exceeds max cost is evicted right away.
+func (l *Lru[K, V]) AddWithCost(key K, value V, cost int64) (eviction bool) {
+	cost = max(cost, 0)
+	l.Remove(key)
+	if l.maxCost > 0 && cost > l.maxCost {
+		if l.evictHandler != nil {
+			l.evictHandler(key, value)
+		}
+		return true
 	}
-	entry := &entry{
-		key:   key,
-		value: value,
+	for (l.evictionList.Len() >= l.capacity || l.maxCost > 0 && l.evictionList.Cost()+cost > l.maxCost) && l.evict() {
+		eviction = true
 	}
-	element := l.evictionList.PushFront(entry)
-	l.elementMap[key] = element
+	l.evictionList.PushFront(key, value, cost)
 	return
 }
 
 // Get returns value of cached entry.
-func (l *Lru) Get(key interface{}, trigger bool) (value interface{}, ok bool) {
-	if element, ok := l.elementMap[key]; ok {
+func (l *Lru[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if entry, ok := l.evictionList.Get(key); ok {
 		if trigger {
-			l.evictionList.MoveToFront(element)
+			l.evictionList.MoveToFront(entry)
 		}
-		return element.Value.(*entry).value, true
+		return entry.Value, true
 	}
-	return nil, false
+	return
 }
 
 // Remove removes cache entry.
-func (l *Lru) Remove(key interface{}) bool {
-	element, ok := l.elementMap[key]
+func (l *Lru[K, V]) Remove(key K) bool {
+	entry, ok := l.evictionList.Get(key)
 	if !ok {
 		return false
 	}
-	delete(l.elementMap, key)
-	l.evictionList.Remove(element)
+	l.evictionList.Remove(entry)
 	return true
 }
 
-func (l *Lru) evict() bool {
-	element := l.evictionList.Back()
-	if element != nil {
-		return l.Remove(element.Value.(*entry).key)
+// OnEvict sets handler called with entries evicted due to capacity.
+func (l *Lru[K, V]) OnEvict(handler func(key K, value V)) {
+	l.evictHandler = handler
+}
+
+func (l *Lru[K, V]) evict() bool {
+	entry := l.evictionList.Back()
+	if entry == nil {
+		return false
+	}
+	l.evictionList.Remove(entry)
+	if l.evictHandler != nil {
+		l.evictHandler(entry.Key, entry.Value)
 	}
-	return false
+	return true
 }
 
 // Clear removes all entries in the cache.
-func (l *Lru) Clear() int {
+func (l *Lru[K, V]) Clear() int {
 	length := l.Len()
-	for key := range l.elementMap {
-		delete(l.elementMap, key)
-	}
-	l.evictionList.Init()
+	l.evictionList.Clear()
 	return length
 }
 
 // Len returns length of the cache.
-func (l *Lru) Len() int {
+func (l *Lru[K, V]) Len() int {
 	return l.evictionList.Len()
 }
 
 // Cap returns capacity of the cache.
-func (l *Lru) Cap() int {
+func (l *Lru[K, V]) Cap() int {
 	return l.capacity
 }
 
+// Cost returns total cost of the entries in the cache.
+func (l *Lru[K, V]) Cost() int64 {
+	return l.evictionList.Cost()
+}
+
+// MaxCost returns max total cost of the cache, zero if unbounded.
+func (l *Lru[K, V]) MaxCost() int64 {
+	return l.maxCost
+}
+
+// SetMaxCost set max total cost of the cache, zero for unbounded.
+// Entries are evicted until the total cost fits the new max cost.
+// Returns error if maxCost is negative value.
+func (l *Lru[K, V]) SetMaxCost(maxCost int64) error {
+	if maxCost < 0 {
+		return errs.ErrNegativeMaxCost
+	}
+	l.maxCost = maxCost
+	for maxCost > 0 && l.evictionList.Cost() > maxCost {
+		l.evict()
+	}
+	return nil
+}
+
+// SetWeigher sets function giving cost of the entries added by Add.
+// Costs of the entries already in the cache are not changed.
+func (l *Lru[K, V]) SetWeigher(weigher func(key K, value V) int64) {
+	l.weigher = weigher
+}
+
+func (l *Lru[K, V]) weigh(key K, value V) int64 {
+	if l.weigher == nil {
+		return 1
+	}
+	return l.weigher(key, value)
+}
+
 // SetCap set capacity of the cache.
+// Entries are evicted until the length fits the new capacity.
 // Returns error unless newCap is negative value.
-func (l *Lru) SetCap(newCapacity int) error {
+func (l *Lru[K, V]) SetCap(newCapacity int) error {
 	if newCapacity <= 0 {
-		return errors.New("capacity must be positive value")
+		return errs.ErrNonPositiveCapacity
 	}
-	if l.capacity < newCapacity {
-		diff := newCapacity - l.capacity
-		for i := 0; i < diff; i++ {
-			l.evict()
-		}
+	for l.evictionList.Len() > newCapacity {
+		l.evict()
 	}
 	l.capacity = newCapacity
 	return nil
 }
 
 // Keys returns a slice of entry keys in the cache.
-func (l *Lru) Keys() []interface{} {
-	keys := make([]interface{}, 0, len(l.elementMap))
-	for k := range l.elementMap {
-		keys = append(keys, k)
-	}
-	return keys
+func (l *Lru[K, V]) Keys() []K {
+	return l.evictionList.Keys()
 }
 
 // Values returns a slice of entry values in the cache.
-func (l *Lru) Values() []interface{} {
-	values := make([]interface{}, 0, len(l.elementMap))
-	for _, v := range l.elementMap {
-		values = append(values, v.Value.(*entry).value)
+func (l *Lru[K, V]) Values() []V {
+	return l.evictionList.Values()
+}
+
+// Range calls fn for entries from the least recently added or used one to
+// the most recent one, until fn returns false.
+func (l *Lru[K, V]) Range(fn func(key K, value V) bool) {
+	for entry := l.evictionList.Back(); entry != nil; entry = entry.Prev() {
+		if !fn(entry.Key, entry.Value) {
+			return
+		}
+	}
+}
+
+// Oldest returns the least recently added or used entry.
+// Returns false if the cache is empty.
+func (l *Lru[K, V]) Oldest() (key K, value V, ok bool) {
+	return entryOf[K, V](l.evictionList.Back())
+}
+
+// Newest returns the most recently added or used entry.
+// Returns false if the cache is empty.
+func (l *Lru[K, V]) Newest() (key K, value V, ok bool) {
+	return entryOf[K, V](l.evictionList.Front())
+}
+
+func entryOf[K comparable, V any](entry *policyutil.Entry[K, V]) (key K, value V, ok bool) {
+	if entry == nil {
+		return
 	}
-	return values
+	return entry.Key, entry.Value, true
 }
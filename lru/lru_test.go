@@ -3,6 +3,7 @@ package lru
 import (
 	"math"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -30,6 +31,27 @@ func TestNewLru(t *testing.T) {
 	}
 }
 
+func TestNew(t *testing.T) {
+	lru, err := New[string, int](1)
+	if lru == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	lru.Add("1", 1)
+	if value, ok := lru.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	lru, err = New[string, int](0)
+	if lru != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
 func TestLru_Add(t *testing.T) {
 	capacity := 10
 	lru, _ := NewLru(capacity)
@@ -223,3 +245,132 @@ func containsAll(s []interface{}, es ...interface{}) bool {
 	}
 	return true
 }
+
+func TestLru_OnEvict(t *testing.T) {
+	capacity := 10
+	lru, _ := NewLru(capacity)
+	evicted := make([]interface{}, 0)
+	lru.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		lru.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	lru.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || lru.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != 0 {
+		t.FailNow()
+	}
+	if _, ok := lru.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	lru.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || lru.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
+
+func TestLru_AddWithCost(t *testing.T) {
+	capacity := 10
+	lru, _ := NewLru(capacity)
+	_ = lru.SetMaxCost(10)
+	lru.AddWithCost(1, "1", 4)
+	lru.AddWithCost(2, "2", 4)
+	if lru.Cost() != 8 || lru.MaxCost() != 10 {
+		t.FailNow()
+	}
+	// 3 does not fit until an entry is evicted
+	if !lru.AddWithCost(3, "3", 4) || lru.Cost() != 8 || lru.Len() != 2 {
+		t.FailNow()
+	}
+	if _, ok := lru.Get(1, false); ok {
+		t.FailNow()
+	}
+	// updating replaces the cost
+	if lru.AddWithCost(3, "3", 1) || lru.Cost() != 5 {
+		t.FailNow()
+	}
+	// entries costlier than max cost are evicted right away
+	evicted := make([]interface{}, 0)
+	lru.OnEvict(func(key, value interface{}) {
+		evicted = append(evicted, key)
+	})
+	if !lru.AddWithCost(4, "4", 11) || lru.Cost() != 5 || len(evicted) != 1 || evicted[0] != 4 {
+		t.FailNow()
+	}
+	if _, ok := lru.Get(4, false); ok {
+		t.FailNow()
+	}
+}
+
+func TestLru_SetMaxCost(t *testing.T) {
+	capacity := 10
+	lru, _ := NewLru(capacity)
+	lru.SetWeigher(func(key, value interface{}) int64 {
+		return int64(len(value.(string)))
+	})
+	for i := 0; i < capacity; i++ {
+		lru.Add(i, strings.Repeat("a", i))
+	}
+	if lru.Cost() != 45 || lru.MaxCost() != 0 {
+		t.FailNow()
+	}
+	if lru.SetMaxCost(-1) == nil {
+		t.FailNow()
+	}
+	if lru.SetMaxCost(20) != nil || lru.Cost() > 20 || lru.MaxCost() != 20 {
+		t.FailNow()
+	}
+	lru.Clear()
+	if lru.Cost() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestLru_Range(t *testing.T) {
+	capacity := 5
+	lru, _ := NewLru(capacity)
+	for i := 0; i < capacity; i++ {
+		lru.Add(i, i)
+	}
+	lru.Get(0, true)
+	keys := make([]interface{}, 0)
+	lru.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+	if len(keys) != 3 {
+		t.FailNow()
+	}
+	for i, key := range keys {
+		if key != i+1 {
+			t.FailNow()
+		}
+	}
+}
+
+func TestLru_Oldest(t *testing.T) {
+	capacity := 5
+	lru, _ := NewLru(capacity)
+	if _, _, ok := lru.Oldest(); ok {
+		t.FailNow()
+	}
+	for i := 0; i < capacity; i++ {
+		lru.Add(i, i)
+	}
+	lru.Get(0, true)
+	if key, value, ok := lru.Oldest(); !ok || key != 1 || value != 1 {
+		t.FailNow()
+	}
+	if key, value, ok := lru.Newest(); !ok || key != 0 || value != 0 {
+		t.FailNow()
+	}
+}
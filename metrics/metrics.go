@@ -0,0 +1,173 @@
+// Package metrics exports statistics of named caches as Prometheus metrics
+// and expvar variables. It is kept apart from the nucleus package, so that
+// caches do not depend on Prometheus.
+package metrics
+
+import (
+	"errors"
+	"expvar"
+	"sort"
+	"sync"
+
+	"github.com/SemihBKGR/nucleus"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "nucleus_cache"
+
+// Source is implemented by caches whose statistics are exported,
+// such as nucleus.Cache and nucleus.ShardedCache.
+type Source interface {
+	Stats() nucleus.Stats
+	Len() int
+	Cap() int
+}
+
+// Registry holds named caches and implements prometheus.Collector.
+type Registry struct {
+	lock    sync.RWMutex
+	sources map[string]Source
+}
+
+var (
+	hitsDesc = prometheus.NewDesc(namespace+"_hits_total",
+		"Count of Get calls which found the entry.", []string{"cache"}, nil)
+	missesDesc = prometheus.NewDesc(namespace+"_misses_total",
+		"Count of Get calls which did not find the entry.", []string{"cache"}, nil)
+	addsDesc = prometheus.NewDesc(namespace+"_adds_total",
+		"Count of added entries.", []string{"cache"}, nil)
+	updatesDesc = prometheus.NewDesc(namespace+"_updates_total",
+		"Count of updated entries.", []string{"cache"}, nil)
+	evictionsDesc = prometheus.NewDesc(namespace+"_evictions_total",
+		"Count of entries left the cache by reason.", []string{"cache", "reason"}, nil)
+	loadsDesc = prometheus.NewDesc(namespace+"_loads_total",
+		"Count of loader calls by result.", []string{"cache", "result"}, nil)
+	loadSecondsDesc = prometheus.NewDesc(namespace+"_load_duration_seconds_total",
+		"Total duration of loader calls in seconds.", []string{"cache"}, nil)
+	entriesDesc = prometheus.NewDesc(namespace+"_entries",
+		"Count of entries in the cache.", []string{"cache"}, nil)
+	capacityDesc = prometheus.NewDesc(namespace+"_capacity",
+		"Capacity of the cache.", []string{"cache"}, nil)
+)
+
+// NewRegistry returns new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		sources: make(map[string]Source),
+	}
+}
+
+// Register adds the cache under the name.
+// Returns error if the name is empty or already registered.
+func (r *Registry) Register(name string, source Source) error {
+	if name == "" {
+		return errors.New("name must not be empty")
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.sources[name]; ok {
+		return errors.New("name is already registered")
+	}
+	r.sources[name] = source
+	return nil
+}
+
+// Unregister removes the cache registered under the name.
+// Returns false if there is no such cache.
+func (r *Registry) Unregister(name string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.sources[name]; !ok {
+		return false
+	}
+	delete(r.sources, name)
+	return true
+}
+
+// Names returns sorted names of the registered caches.
+func (r *Registry) Names() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	names := make([]string, 0, len(r.sources))
+	for name := range r.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Describe implements prometheus.Collector.
+func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		hitsDesc, missesDesc, addsDesc, updatesDesc, evictionsDesc,
+		loadsDesc, loadSecondsDesc, entriesDesc, capacityDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (r *Registry) Collect(ch chan<- prometheus.Metric) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for name, source := range r.sources {
+		stats := source.Stats()
+		counter := func(desc *prometheus.Desc, value float64, labels ...string) {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, append([]string{name}, labels...)...)
+		}
+		counter(hitsDesc, float64(stats.Hits))
+		counter(missesDesc, float64(stats.Misses))
+		counter(addsDesc, float64(stats.Adds))
+		counter(updatesDesc, float64(stats.Updates))
+		for reason, count := range stats.Evictions {
+			counter(evictionsDesc, float64(count), reason.String())
+		}
+		counter(loadsDesc, float64(stats.LoadSuccesses), "success")
+		counter(loadsDesc, float64(stats.LoadFailures), "failure")
+		counter(loadSecondsDesc, stats.TotalLoadTime.Seconds())
+		ch <- prometheus.MustNewConstMetric(entriesDesc, prometheus.GaugeValue, float64(source.Len()), name)
+		ch <- prometheus.MustNewConstMetric(capacityDesc, prometheus.GaugeValue, float64(source.Cap()), name)
+	}
+}
+
+// Expvar returns expvar variable mapping names of the registered caches to
+// their statistics, evaluated whenever the variable is read.
+func (r *Registry) Expvar() expvar.Var {
+	return expvar.Func(func() any {
+		r.lock.RLock()
+		defer r.lock.RUnlock()
+		vars := make(map[string]map[string]any, len(r.sources))
+		for name, source := range r.sources {
+			vars[name] = expvarStats(source)
+		}
+		return vars
+	})
+}
+
+// PublishExpvar publishes the registry as expvar variable under the name.
+// Like expvar.Publish, it panics if the name is already in use.
+func (r *Registry) PublishExpvar(name string) {
+	expvar.Publish(name, r.Expvar())
+}
+
+func expvarStats(source Source) map[string]any {
+	stats := source.Stats()
+	evictions := make(map[string]uint64, len(stats.Evictions))
+	for reason, count := range stats.Evictions {
+		evictions[reason.String()] = count
+	}
+	return map[string]any{
+		"hits":             stats.Hits,
+		"misses":           stats.Misses,
+		"hit_ratio":        stats.HitRatio(),
+		"adds":             stats.Adds,
+		"updates":          stats.Updates,
+		"evictions":        evictions,
+		"expirations":      stats.Expirations,
+		"load_successes":   stats.LoadSuccesses,
+		"load_failures":    stats.LoadFailures,
+		"load_duration_ns": stats.TotalLoadTime.Nanoseconds(),
+		"entries":          source.Len(),
+		"capacity":         source.Cap(),
+	}
+}
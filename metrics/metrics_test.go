@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/SemihBKGR/nucleus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func newCaches(t *testing.T) (*nucleus.Cache[interface{}, interface{}], *nucleus.ShardedCache[interface{}, interface{}]) {
+	cache, err := nucleus.NewLruCache(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Add(1, "1")
+	cache.Add(2, "2")
+	cache.Add(3, "3")
+	cache.Get(3)
+	cache.Get(1)
+	sharded, err := nucleus.NewShardedCache(2, 4, nucleus.NewLruCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharded.Add(1, "1")
+	return cache, sharded
+}
+
+func TestRegistry_Register(t *testing.T) {
+	cache, sharded := newCaches(t)
+	registry := NewRegistry()
+	if registry.Register("", cache) == nil {
+		t.FailNow()
+	}
+	if registry.Register("users", cache) != nil || registry.Register("sessions", sharded) != nil {
+		t.FailNow()
+	}
+	if registry.Register("users", sharded) == nil {
+		t.FailNow()
+	}
+	if names := registry.Names(); len(names) != 2 || names[0] != "sessions" || names[1] != "users" {
+		t.FailNow()
+	}
+	if !registry.Unregister("sessions") || registry.Unregister("sessions") {
+		t.FailNow()
+	}
+	if names := registry.Names(); len(names) != 1 {
+		t.FailNow()
+	}
+}
+
+func TestRegistry_Collect(t *testing.T) {
+	cache, sharded := newCaches(t)
+	registry := NewRegistry()
+	_ = registry.Register("users", cache)
+	_ = registry.Register("sessions", sharded)
+	prometheusRegistry := prometheus.NewPedanticRegistry()
+	if err := prometheusRegistry.Register(registry); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(promhttp.HandlerFor(prometheusRegistry, promhttp.HandlerOpts{}))
+	defer server.Close()
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	text := string(body)
+	for _, line := range []string{
+		`nucleus_cache_hits_total{cache="users"} 1`,
+		`nucleus_cache_misses_total{cache="users"} 1`,
+		`nucleus_cache_adds_total{cache="users"} 3`,
+		`nucleus_cache_evictions_total{cache="users",reason="capacity"} 1`,
+		`nucleus_cache_loads_total{cache="users",result="success"} 0`,
+		`nucleus_cache_entries{cache="users"} 2`,
+		`nucleus_cache_capacity{cache="users"} 2`,
+		`nucleus_cache_adds_total{cache="sessions"} 1`,
+		`nucleus_cache_capacity{cache="sessions"} 4`,
+		`# TYPE nucleus_cache_hits_total counter`,
+		`# TYPE nucleus_cache_entries gauge`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Fatal(line)
+		}
+	}
+}
+
+var published int32
+
+func TestRegistry_Expvar(t *testing.T) {
+	cache, _ := newCaches(t)
+	registry := NewRegistry()
+	_ = registry.Register("users", cache)
+	// expvar names can not be published twice, e.g. with -count
+	name := fmt.Sprintf("nucleus_test_%d", atomic.AddInt32(&published, 1))
+	registry.PublishExpvar(name)
+	server := httptest.NewServer(expvar.Handler())
+	defer server.Close()
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	vars := make(map[string]json.RawMessage)
+	if err := json.NewDecoder(response.Body).Decode(&vars); err != nil {
+		t.Fatal(err)
+	}
+	caches := make(map[string]struct {
+		Hits      uint64            `json:"hits"`
+		HitRatio  float64           `json:"hit_ratio"`
+		Evictions map[string]uint64 `json:"evictions"`
+		Entries   int               `json:"entries"`
+	})
+	if err := json.Unmarshal(vars[name], &caches); err != nil {
+		t.Fatal(err)
+	}
+	users, ok := caches["users"]
+	if !ok || users.Hits != 1 || users.HitRatio != 0.5 || users.Evictions["capacity"] != 1 || users.Entries != 2 {
+		t.FailNow()
+	}
+}
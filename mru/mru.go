@@ -1,135 +1,202 @@
 package mru
 
 import (
-	"container/list"
-	"errors"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+	"github.com/SemihBKGR/nucleus/policyutil"
 )
 
 // Mru Most recently used policy
-type Mru struct {
+type Mru[K comparable, V any] struct {
 	capacity     int
-	elementMap   map[interface{}]*list.Element
-	evictionList *list.List
+	evictionList *policyutil.List[K, V]
+	evictHandler func(key K, value V)
+	weigher      func(key K, value V) int64
+	maxCost      int64
 }
 
-type entry struct {
-	key   interface{}
-	value interface{}
-}
-
-// NewMru returns new mru
-func NewMru(capacity int) (*Mru, error) {
+// New returns new typed mru
+func New[K comparable, V any](capacity int) (*Mru[K, V], error) {
 	if capacity <= 0 {
-		return nil, errors.New("capacity must be positive value")
+		return nil, errs.ErrNonPositiveCapacity
 	}
-	lru := &Mru{
+	lru := &Mru[K, V]{
 		capacity:     capacity,
-		elementMap:   make(map[interface{}]*list.Element),
-		evictionList: list.New(),
+		evictionList: policyutil.NewList[K, V](),
 	}
 	return lru, nil
 }
 
+// NewMru returns new mru
+func NewMru(capacity int) (*Mru[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity)
+}
+
 // Add adds entry in cache
-func (m *Mru) Add(key, value interface{}) (eviction bool) {
-	if element, ok := m.elementMap[key]; ok {
-		m.evictionList.MoveToFront(element)
-		element.Value.(*entry).value = value
-		return false
-	}
-	eviction = len(m.elementMap) >= m.capacity
-	if eviction {
-		m.evict()
+// Cost of the entry is given by the weigher, or 1 if there is none.
+func (m *Mru[K, V]) Add(key K, value V) (eviction bool) {
+	return m.AddWithCost(key, value, m.weigh(key, value))
+}
+
+// AddWithCost adds entry in cache with given cost.
+// Entries are evicted until both length and total cost fit, an entry whose
+// cost exceeds max cost is evicted right away.
+func (m *Mru[K, V]) AddWithCost(key K, value V, cost int64) (eviction bool) {
+	cost = max(cost, 0)
+	m.Remove(key)
+	if m.maxCost > 0 && cost > m.maxCost {
+		if m.evictHandler != nil {
+			m.evictHandler(key, value)
+		}
+		return true
 	}
-	entry := &entry{
-		key:   key,
-		value: value,
+	for (m.evictionList.Len() >= m.capacity || m.maxCost > 0 && m.evictionList.Cost()+cost > m.maxCost) && m.evict() {
+		eviction = true
 	}
-	element := m.evictionList.PushFront(entry)
-	m.elementMap[key] = element
+	m.evictionList.PushFront(key, value, cost)
 	return
 }
 
 // Get returns value of cached entry.
-func (m *Mru) Get(key interface{}, trigger bool) (value interface{}, ok bool) {
-	if element, ok := m.elementMap[key]; ok {
+func (m *Mru[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if entry, ok := m.evictionList.Get(key); ok {
 		if trigger {
-			m.evictionList.MoveToFront(element)
+			m.evictionList.MoveToFront(entry)
 		}
-		return element.Value.(*entry).value, true
+		return entry.Value, true
 	}
-	return nil, false
+	return
 }
 
 // Remove removes cache entry.
-func (m *Mru) Remove(key interface{}) bool {
-	element, ok := m.elementMap[key]
+func (m *Mru[K, V]) Remove(key K) bool {
+	entry, ok := m.evictionList.Get(key)
 	if !ok {
 		return false
 	}
-	delete(m.elementMap, key)
-	m.evictionList.Remove(element)
+	m.evictionList.Remove(entry)
 	return true
 }
 
-func (m *Mru) evict() bool {
-	element := m.evictionList.Front()
-	if element != nil {
-		return m.Remove(element.Value.(*entry).key)
+// OnEvict sets handler called with entries evicted due to capacity.
+func (m *Mru[K, V]) OnEvict(handler func(key K, value V)) {
+	m.evictHandler = handler
+}
+
+func (m *Mru[K, V]) evict() bool {
+	entry := m.evictionList.Front()
+	if entry == nil {
+		return false
+	}
+	m.evictionList.Remove(entry)
+	if m.evictHandler != nil {
+		m.evictHandler(entry.Key, entry.Value)
 	}
-	return false
+	return true
 }
 
 // Clear removes all entries in the cache.
-func (m *Mru) Clear() int {
+func (m *Mru[K, V]) Clear() int {
 	length := m.Len()
-	for key := range m.elementMap {
-		delete(m.elementMap, key)
-	}
-	m.evictionList.Init()
+	m.evictionList.Clear()
 	return length
 }
 
 // Len returns length of the cache.
-func (m *Mru) Len() int {
+func (m *Mru[K, V]) Len() int {
 	return m.evictionList.Len()
 }
 
 // Cap returns capacity of the cache.
-func (m *Mru) Cap() int {
+func (m *Mru[K, V]) Cap() int {
 	return m.capacity
 }
 
+// Cost returns total cost of the entries in the cache.
+func (m *Mru[K, V]) Cost() int64 {
+	return m.evictionList.Cost()
+}
+
+// MaxCost returns max total cost of the cache, zero if unbounded.
+func (m *Mru[K, V]) MaxCost() int64 {
+	return m.maxCost
+}
+
+// SetMaxCost set max total cost of the cache, zero for unbounded.
+// Entries are evicted until the total cost fits the new max cost.
+// Returns error if maxCost is negative value.
+func (m *Mru[K, V]) SetMaxCost(maxCost int64) error {
+	if maxCost < 0 {
+		return errs.ErrNegativeMaxCost
+	}
+	m.maxCost = maxCost
+	for maxCost > 0 && m.evictionList.Cost() > maxCost {
+		m.evict()
+	}
+	return nil
+}
+
+// SetWeigher sets function giving cost of the entries added by Add.
+// Costs of the entries already in the cache are not changed.
+func (m *Mru[K, V]) SetWeigher(weigher func(key K, value V) int64) {
+	m.weigher = weigher
+}
+
+func (m *Mru[K, V]) weigh(key K, value V) int64 {
+	if m.weigher == nil {
+		return 1
+	}
+	return m.weigher(key, value)
+}
+
 // SetCap set capacity of the cache.
+// Entries are evicted until the length fits the new capacity.
 // Returns error unless newCap is negative value.
-func (m *Mru) SetCap(newCapacity int) error {
+func (m *Mru[K, V]) SetCap(newCapacity int) error {
 	if newCapacity <= 0 {
-		return errors.New("capacity must be positive value")
+		return errs.ErrNonPositiveCapacity
 	}
-	if m.capacity < newCapacity {
-		diff := newCapacity - m.capacity
-		for i := 0; i < diff; i++ {
-			m.evict()
-		}
+	for m.evictionList.Len() > newCapacity {
+		m.evict()
 	}
 	m.capacity = newCapacity
 	return nil
 }
 
 // Keys returns a slice of entry keys in the cache.
-func (m *Mru) Keys() []interface{} {
-	keys := make([]interface{}, 0, len(m.elementMap))
-	for k := range m.elementMap {
-		keys = append(keys, k)
-	}
-	return keys
+func (m *Mru[K, V]) Keys() []K {
+	return m.evictionList.Keys()
 }
 
 // Values returns a slice of entry values in the cache.
-func (m *Mru) Values() []interface{} {
-	values := make([]interface{}, 0, len(m.elementMap))
-	for _, v := range m.elementMap {
-		values = append(values, v.Value.(*entry).value)
+func (m *Mru[K, V]) Values() []V {
+	return m.evictionList.Values()
+}
+
+// Range calls fn for entries from the least recently added or used one to
+// the most recent one, until fn returns false.
+func (m *Mru[K, V]) Range(fn func(key K, value V) bool) {
+	for entry := m.evictionList.Back(); entry != nil; entry = entry.Prev() {
+		if !fn(entry.Key, entry.Value) {
+			return
+		}
+	}
+}
+
+// Oldest returns the least recently added or used entry.
+// Returns false if the cache is empty.
+func (m *Mru[K, V]) Oldest() (key K, value V, ok bool) {
+	return entryOf[K, V](m.evictionList.Back())
+}
+
+// Newest returns the most recently added or used entry.
+// Returns false if the cache is empty.
+func (m *Mru[K, V]) Newest() (key K, value V, ok bool) {
+	return entryOf[K, V](m.evictionList.Front())
+}
+
+func entryOf[K comparable, V any](entry *policyutil.Entry[K, V]) (key K, value V, ok bool) {
+	if entry == nil {
+		return
 	}
-	return values
+	return entry.Key, entry.Value, true
 }
@@ -3,6 +3,7 @@ package mru
 import (
 	"math"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -30,6 +31,27 @@ func TestNewMru(t *testing.T) {
 	}
 }
 
+func TestNew(t *testing.T) {
+	mru, err := New[string, int](1)
+	if mru == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	mru.Add("1", 1)
+	if value, ok := mru.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	mru, err = New[string, int](0)
+	if mru != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
 func TestMru_Add(t *testing.T) {
 	capacity := 10
 	mru, _ := NewMru(capacity)
@@ -223,3 +245,132 @@ func containsAll(s []interface{}, es ...interface{}) bool {
 	}
 	return true
 }
+
+func TestMru_OnEvict(t *testing.T) {
+	capacity := 10
+	mru, _ := NewMru(capacity)
+	evicted := make([]interface{}, 0)
+	mru.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		mru.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	mru.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || mru.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != capacity-1 {
+		t.FailNow()
+	}
+	if _, ok := mru.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	mru.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || mru.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
+
+func TestMru_AddWithCost(t *testing.T) {
+	capacity := 10
+	mru, _ := NewMru(capacity)
+	_ = mru.SetMaxCost(10)
+	mru.AddWithCost(1, "1", 4)
+	mru.AddWithCost(2, "2", 4)
+	if mru.Cost() != 8 || mru.MaxCost() != 10 {
+		t.FailNow()
+	}
+	// 3 does not fit until an entry is evicted
+	if !mru.AddWithCost(3, "3", 4) || mru.Cost() != 8 || mru.Len() != 2 {
+		t.FailNow()
+	}
+	if _, ok := mru.Get(2, false); ok {
+		t.FailNow()
+	}
+	// updating replaces the cost
+	if mru.AddWithCost(3, "3", 1) || mru.Cost() != 5 {
+		t.FailNow()
+	}
+	// entries costlier than max cost are evicted right away
+	evicted := make([]interface{}, 0)
+	mru.OnEvict(func(key, value interface{}) {
+		evicted = append(evicted, key)
+	})
+	if !mru.AddWithCost(4, "4", 11) || mru.Cost() != 5 || len(evicted) != 1 || evicted[0] != 4 {
+		t.FailNow()
+	}
+	if _, ok := mru.Get(4, false); ok {
+		t.FailNow()
+	}
+}
+
+func TestMru_SetMaxCost(t *testing.T) {
+	capacity := 10
+	mru, _ := NewMru(capacity)
+	mru.SetWeigher(func(key, value interface{}) int64 {
+		return int64(len(value.(string)))
+	})
+	for i := 0; i < capacity; i++ {
+		mru.Add(i, strings.Repeat("a", i))
+	}
+	if mru.Cost() != 45 || mru.MaxCost() != 0 {
+		t.FailNow()
+	}
+	if mru.SetMaxCost(-1) == nil {
+		t.FailNow()
+	}
+	if mru.SetMaxCost(20) != nil || mru.Cost() > 20 || mru.MaxCost() != 20 {
+		t.FailNow()
+	}
+	mru.Clear()
+	if mru.Cost() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestMru_Range(t *testing.T) {
+	capacity := 5
+	mru, _ := NewMru(capacity)
+	for i := 0; i < capacity; i++ {
+		mru.Add(i, i)
+	}
+	mru.Get(0, true)
+	keys := make([]interface{}, 0)
+	mru.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+	if len(keys) != 3 {
+		t.FailNow()
+	}
+	for i, key := range keys {
+		if key != i+1 {
+			t.FailNow()
+		}
+	}
+}
+
+func TestMru_Oldest(t *testing.T) {
+	capacity := 5
+	mru, _ := NewMru(capacity)
+	if _, _, ok := mru.Oldest(); ok {
+		t.FailNow()
+	}
+	for i := 0; i < capacity; i++ {
+		mru.Add(i, i)
+	}
+	mru.Get(0, true)
+	if key, value, ok := mru.Oldest(); !ok || key != 1 || value != 1 {
+		t.FailNow()
+	}
+	if key, value, ok := mru.Newest(); !ok || key != 0 || value != 0 {
+		t.FailNow()
+	}
+}
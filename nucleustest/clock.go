@@ -0,0 +1,90 @@
+// Package nucleustest provides utilities for testing code using nucleus
+// caches.
+package nucleustest
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock whose time moves only when it is advanced.
+// It implements nucleus.Clock and tlru.Clock, so that entries expire and
+// expiration daemons sweep deterministically.
+type FakeClock struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []waiter
+}
+
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFakeClock returns new fake clock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	clock := &FakeClock{now: now}
+	clock.cond = sync.NewCond(&clock.lock)
+	return clock
+}
+
+// Now returns current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// After returns channel receiving the time once the clock is advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, waiter{deadline: c.now.Add(d), ch: ch})
+	c.cond.Broadcast()
+	return ch
+}
+
+// Advance moves the clock forward by d, and fires channels of After whose
+// durations have passed in deadline order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+	waiting := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			waiting = append(waiting, w)
+		} else {
+			w.ch <- c.now
+		}
+	}
+	c.waiters = waiting
+}
+
+// Waiters returns count of channels of After which have not fired yet.
+func (c *FakeClock) Waiters() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until count of channels of After which have not fired
+// yet is at least n, e.g. until an expiration daemon waits for its next
+// sweep.
+func (c *FakeClock) BlockUntil(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
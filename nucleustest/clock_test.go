@@ -0,0 +1,54 @@
+package nucleustest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	if !clock.Now().Equal(start) {
+		t.FailNow()
+	}
+	second := clock.After(2 * time.Second)
+	first := clock.After(time.Second)
+	select {
+	case <-clock.After(0):
+	default:
+		t.FailNow()
+	}
+	clock.Advance(time.Second)
+	select {
+	case now := <-first:
+		if !now.Equal(start.Add(time.Second)) {
+			t.FailNow()
+		}
+	default:
+		t.FailNow()
+	}
+	select {
+	case <-second:
+		t.FailNow()
+	default:
+	}
+	if clock.Waiters() != 1 {
+		t.FailNow()
+	}
+	clock.Advance(time.Second)
+	if len(second) != 1 || clock.Waiters() != 0 || !clock.Now().Equal(start.Add(2*time.Second)) {
+		t.FailNow()
+	}
+}
+
+func TestFakeClock_BlockUntil(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	done := make(chan struct{})
+	go func() {
+		<-clock.After(time.Minute)
+		close(done)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	<-done
+}
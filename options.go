@@ -0,0 +1,337 @@
+package nucleus
+
+import (
+	"context"
+	"fmt"
+	"github.com/SemihBKGR/nucleus/arc"
+	"github.com/SemihBKGR/nucleus/clock"
+	"github.com/SemihBKGR/nucleus/clockpro"
+	"github.com/SemihBKGR/nucleus/fifo"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+	"github.com/SemihBKGR/nucleus/lfu"
+	"github.com/SemihBKGR/nucleus/lru"
+	"github.com/SemihBKGR/nucleus/mru"
+	"github.com/SemihBKGR/nucleus/slru"
+	"github.com/SemihBKGR/nucleus/tinylfu"
+	"github.com/SemihBKGR/nucleus/tlru"
+	"github.com/SemihBKGR/nucleus/twoq"
+	"sync"
+	"time"
+)
+
+// PolicyType selects eviction policy of caches created by New.
+type PolicyType int
+
+// Policy types of the policy packages.
+const (
+	// LruPolicy evicts the least recently used entry.
+	LruPolicy PolicyType = iota + 1
+	// MruPolicy evicts the most recently used entry.
+	MruPolicy
+	// FifoPolicy evicts the first added entry.
+	FifoPolicy
+	// TlruPolicy evicts the least recently used entry and expires entries
+	// after their ttl.
+	TlruPolicy
+	// LfuPolicy evicts the least frequently used entry.
+	LfuPolicy
+	// ArcPolicy adapts between recency and frequency by ghost entries.
+	ArcPolicy
+	// TinyLfuPolicy admits entries by their estimated frequency.
+	TinyLfuPolicy
+	// TwoQPolicy keeps entries used only once apart from the frequent ones.
+	TwoQPolicy
+	// SlruPolicy promotes entries used again into a protected segment.
+	SlruPolicy
+	// ClockPolicy approximates lru by reference bits.
+	ClockPolicy
+	// ClockProPolicy approximates lirs by hot and cold clock hands.
+	ClockProPolicy
+)
+
+var policyNames = map[PolicyType]string{
+	LruPolicy:      "lru",
+	MruPolicy:      "mru",
+	FifoPolicy:     "fifo",
+	TlruPolicy:     "tlru",
+	LfuPolicy:      "lfu",
+	ArcPolicy:      "arc",
+	TinyLfuPolicy:  "tinylfu",
+	TwoQPolicy:     "twoq",
+	SlruPolicy:     "slru",
+	ClockPolicy:    "clock",
+	ClockProPolicy: "clockpro",
+}
+
+// String returns name of the policy type.
+func (p PolicyType) String() string {
+	if name, ok := policyNames[p]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Option configures cache created by New.
+type Option func(*options)
+
+type options struct {
+	policyType    PolicyType
+	policy        any
+	capacity      int
+	ttl           time.Duration
+	sweepInterval time.Duration
+	sweepSet      bool
+	onEvict       any
+	stats         bool
+	clock         Clock
+	clockSet      bool
+}
+
+// OptionError is returned by New if the options are invalid or the policy
+// can not be created with them.
+type OptionError struct {
+	// Option is name of the invalid option, such as "WithCapacity".
+	Option string
+	// Reason describes why the option is invalid, if Err does not.
+	Reason string
+	// Err is the cause, such as ErrInvalidCapacity or the error returned
+	// by the policy.
+	Err error
+}
+
+// Error returns the option name with the reason and the cause.
+func (e *OptionError) Error() string {
+	switch {
+	case e.Reason == "":
+		return fmt.Sprintf("nucleus: %s: %v", e.Option, e.Err)
+	case e.Err != nil:
+		return fmt.Sprintf("nucleus: %s: %s: %v", e.Option, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("nucleus: %s: %s", e.Option, e.Reason)
+}
+
+// Unwrap returns the cause.
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// WithPolicy sets eviction policy of the cache.
+// Defaults to TlruPolicy if a ttl is set, LruPolicy otherwise.
+// TwoQPolicy and SlruPolicy are created with their default ratios.
+func WithPolicy(policyType PolicyType) Option {
+	return func(o *options) {
+		o.policyType = policyType
+	}
+}
+
+// withPolicy sets an already created policy, whose capacity is used.
+func withPolicy[K comparable, V any](policy Policy[K, V]) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithCapacity sets capacity of the cache, it is required.
+func WithCapacity(capacity int) Option {
+	return func(o *options) {
+		o.capacity = capacity
+	}
+}
+
+// WithTTL sets time to live of the entries added without explicit ttl.
+// It is supported by TlruPolicy only, zero ttl never expires the entries.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithSweepInterval sets interval of the expiration daemon sweeps of
+// TlruPolicy, or of a policy given to NewWithPolicy which runs such a
+// daemon, zero for not starting the daemon. Defaults to the ttl.
+// The daemon runs until Cache.Close is called, which is required to stop it
+// whenever the interval is positive.
+func WithSweepInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.sweepInterval = interval
+		o.sweepSet = true
+	}
+}
+
+// WithEvictionCallback sets callback notified with the entries leaving the
+// cache, as Cache.OnEvict. Its types must match the types of the cache.
+func WithEvictionCallback[K comparable, V any](callback EvictionCallback[K, V]) Option {
+	return func(o *options) {
+		o.onEvict = callback
+	}
+}
+
+// WithClock sets clock of the cache, defaults to the system clock.
+// It is used by negative ttl of the loaders and given to policies taking
+// time from a clock, such as tlru.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+		o.clockSet = true
+	}
+}
+
+// WithStats enables recording of the statistics returned by Cache.Stats.
+func WithStats() Option {
+	return func(o *options) {
+		o.stats = true
+	}
+}
+
+// New returns new typed cache configured by the options,
+// e.g. New[string, int](WithPolicy(LruPolicy), WithCapacity(1024), WithStats()).
+// The expiration daemon is started whenever the sweep interval is positive,
+// which is the default for TlruPolicy with a ttl, and it runs until
+// Cache.Close is called, so such caches must be closed to release it.
+// Returns *OptionError if the options are invalid.
+func New[K comparable, V any](opts ...Option) (*Cache[K, V], error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	var policy Policy[K, V]
+	if o.policy != nil {
+		var ok bool
+		if policy, ok = o.policy.(Policy[K, V]); !ok {
+			return nil, &OptionError{Option: "WithPolicy", Reason: "policy types do not match cache types"}
+		}
+	} else {
+		var err error
+		if policy, err = newTypedPolicy[K, V](&o); err != nil {
+			return nil, err
+		}
+	}
+	cache := newCache[K, V](policy)
+	cache.stats.enabled = o.stats
+	if o.onEvict != nil {
+		callback, ok := o.onEvict.(EvictionCallback[K, V])
+		if !ok {
+			return nil, &OptionError{Option: "WithEvictionCallback", Reason: "callback types do not match cache types"}
+		}
+		cache.onEvict = callback
+	}
+	cache.loadGroup.clock = o.clock
+	if clockedPolicy, ok := policy.(clockedPolicy); ok && o.clock != nil {
+		clockedPolicy.SetClock(o.clock)
+	}
+	if sweepingPolicy, ok := policy.(sweepingPolicy); ok && o.sweepInterval > 0 {
+		if err := sweepingPolicy.SetSweepInterval(o.sweepInterval); err != nil {
+			return nil, &OptionError{Option: "WithSweepInterval", Reason: "invalid sweep interval", Err: err}
+		}
+		sweepingPolicy.StartDaemonContext(context.Background(), cacheLocker[K, V]{cache})
+	}
+	return cache, nil
+}
+
+// NewWithPolicy returns new typed cache evicting entries by the policy,
+// which may be a custom implementation of Policy. The optional interfaces
+// such as EvictingPolicy and ExpirablePolicy implemented by the policy are
+// used by the cache. The policy must not be used outside the cache, which
+// serializes access to it.
+// WithPolicy, WithCapacity and WithTTL can not be given, since they create
+// the policy. Returns *OptionError if the policy is nil or the options are
+// invalid.
+func NewWithPolicy[K comparable, V any](policy Policy[K, V], opts ...Option) (*Cache[K, V], error) {
+	if policy == nil {
+		return nil, &OptionError{Option: "NewWithPolicy", Reason: "policy must not be nil"}
+	}
+	return New[K, V](append([]Option{withPolicy[K, V](policy)}, opts...)...)
+}
+
+// sweepingPolicy is implemented by policies removing expired entries by a
+// daemon, such as tlru, whose sweeps are set by WithSweepInterval.
+type sweepingPolicy interface {
+	SetSweepInterval(interval time.Duration) error
+	StartDaemonContext(ctx context.Context, lock sync.Locker) bool
+}
+
+// clockedPolicy is implemented by policies taking time from a clock, such
+// as tlru, which are given the clock of WithClock.
+type clockedPolicy interface {
+	SetClock(clock tlru.Clock)
+}
+
+// validate validates combination of the options and fills the defaults.
+func (o *options) validate() error {
+	if o.clockSet && o.clock == nil {
+		return &OptionError{Option: "WithClock", Reason: "clock must not be nil"}
+	}
+	if o.sweepInterval < 0 {
+		return &OptionError{Option: "WithSweepInterval", Err: fmt.Errorf("%w: sweep interval must not be negative value", ErrInvalidTTL)}
+	}
+	if o.policy != nil {
+		if o.policyType != 0 || o.capacity != 0 || o.ttl != 0 {
+			return &OptionError{Option: "WithPolicy", Reason: "policy is given with policy type, capacity or ttl"}
+		}
+		if _, ok := o.policy.(sweepingPolicy); o.sweepSet && !ok {
+			return &OptionError{Option: "WithSweepInterval", Reason: "policy does not expire entries"}
+		}
+		return nil
+	}
+	if o.policyType == 0 {
+		o.policyType = LruPolicy
+		if o.ttl > 0 {
+			o.policyType = TlruPolicy
+		}
+	}
+	if _, ok := policyNames[o.policyType]; !ok {
+		return &OptionError{Option: "WithPolicy", Reason: fmt.Sprintf("unknown policy type %d", o.policyType)}
+	}
+	if o.capacity <= 0 {
+		return &OptionError{Option: "WithCapacity", Err: errs.ErrNonPositiveCapacity}
+	}
+	if o.ttl < 0 {
+		return &OptionError{Option: "WithTTL", Err: fmt.Errorf("%w: must not be negative value", ErrInvalidTTL)}
+	}
+	if o.policyType != TlruPolicy {
+		if o.ttl > 0 {
+			return &OptionError{Option: "WithTTL", Reason: o.policyType.String() + " policy", Err: unsupported("ttl")}
+		}
+		if o.sweepSet {
+			return &OptionError{Option: "WithSweepInterval", Reason: o.policyType.String() + " policy does not expire entries"}
+		}
+	} else if !o.sweepSet {
+		o.sweepInterval = o.ttl
+	}
+	return nil
+}
+
+// newTypedPolicy creates the policy of the policy type with the cache types.
+func newTypedPolicy[K comparable, V any](o *options) (policy Policy[K, V], err error) {
+	switch o.policyType {
+	case LruPolicy:
+		policy, err = lru.New[K, V](o.capacity)
+	case MruPolicy:
+		policy, err = mru.New[K, V](o.capacity)
+	case FifoPolicy:
+		policy, err = fifo.New[K, V](o.capacity)
+	case TlruPolicy:
+		policy, err = tlru.New[K, V](o.capacity, o.ttl)
+	case LfuPolicy:
+		policy, err = lfu.New[K, V](o.capacity)
+	case ArcPolicy:
+		policy, err = arc.New[K, V](o.capacity)
+	case TinyLfuPolicy:
+		policy, err = tinylfu.New[K, V](o.capacity)
+	case TwoQPolicy:
+		policy, err = twoq.New[K, V](o.capacity, twoq.DefaultRecentRatio, twoq.DefaultGhostRatio)
+	case SlruPolicy:
+		policy, err = slru.New[K, V](o.capacity, slru.DefaultProtectedRatio)
+	case ClockPolicy:
+		policy, err = clock.New[K, V](o.capacity)
+	case ClockProPolicy:
+		policy, err = clockpro.New[K, V](o.capacity)
+	}
+	if err != nil {
+		return nil, &OptionError{Option: "WithPolicy", Reason: "policy can not be created", Err: err}
+	}
+	return policy, nil
+}
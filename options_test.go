@@ -0,0 +1,218 @@
+package nucleus
+
+import (
+	"errors"
+	"github.com/SemihBKGR/nucleus/lru"
+	"github.com/SemihBKGR/nucleus/nucleustest"
+	"github.com/SemihBKGR/nucleus/tlru"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	capacity := 2
+	evicted := make([]string, 0)
+	cache, err := New[string, int](
+		WithPolicy(FifoPolicy),
+		WithCapacity(capacity),
+		WithEvictionCallback(func(key string, value int, reason EvictReason) {
+			evicted = append(evicted, key)
+		}),
+	)
+	if err != nil || cache.Cap() != capacity {
+		t.FailNow()
+	}
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	cache.Get("a")
+	cache.Add("c", 3)
+	if len(evicted) != 1 || evicted[0] != "a" {
+		t.FailNow()
+	}
+	// statistics are not recorded without WithStats
+	if cache.Stats().Adds != 0 || cache.Stats().Hits != 0 {
+		t.FailNow()
+	}
+	cache, _ = New[string, int](WithCapacity(capacity), WithStats())
+	cache.Add("a", 1)
+	if _, ok := cache.policy.(*lru.Lru[string, int]); !ok || cache.Stats().Adds != 1 {
+		t.FailNow()
+	}
+}
+
+func TestNew2(t *testing.T) {
+	capacity := 10
+	cache, err := New[int, int](WithCapacity(capacity), WithTTL(10*time.Millisecond))
+	if err != nil || !cache.expirable() {
+		t.FailNow()
+	}
+	defer cache.Close()
+	cache.Add(1, 1)
+	time.Sleep(20 * time.Millisecond)
+	if cache.Len() != 0 {
+		t.FailNow()
+	}
+	cache, err = New[int, int](WithPolicy(TlruPolicy), WithCapacity(capacity), WithSweepInterval(0))
+	if err != nil || cache.policy.(*tlru.Tlru[int, int]).DaemonStarted() {
+		t.FailNow()
+	}
+	for _, policyType := range []PolicyType{
+		LruPolicy, MruPolicy, FifoPolicy, TlruPolicy, LfuPolicy, ArcPolicy,
+		TinyLfuPolicy, TwoQPolicy, SlruPolicy, ClockPolicy, ClockProPolicy,
+	} {
+		cache, err := New[int, int](WithPolicy(policyType), WithCapacity(capacity))
+		if err != nil || cache.Cap() != capacity {
+			t.Fatal(policyType)
+		}
+		cache.Close()
+	}
+}
+
+func TestNew3(t *testing.T) {
+	tests := []struct {
+		option string
+		opts   []Option
+	}{
+		{"WithCapacity", nil},
+		{"WithCapacity", []Option{WithPolicy(LruPolicy), WithCapacity(-1)}},
+		{"WithPolicy", []Option{WithPolicy(PolicyType(100)), WithCapacity(1)}},
+		{"WithTTL", []Option{WithCapacity(1), WithTTL(-time.Second)}},
+		{"WithTTL", []Option{WithPolicy(LruPolicy), WithCapacity(1), WithTTL(time.Second)}},
+		{"WithSweepInterval", []Option{WithPolicy(ArcPolicy), WithCapacity(1), WithSweepInterval(time.Second)}},
+		{"WithSweepInterval", []Option{WithCapacity(1), WithTTL(time.Second), WithSweepInterval(-time.Second)}},
+		{"WithEvictionCallback", []Option{WithCapacity(1), WithEvictionCallback(func(key int, value string, reason EvictReason) {})}},
+	}
+	for _, test := range tests {
+		cache, err := New[int, int](test.opts...)
+		var optionErr *OptionError
+		if cache != nil || !errors.As(err, &optionErr) || optionErr.Option != test.option {
+			t.Fatal(test.option, err)
+		}
+	}
+	err := &OptionError{Option: "WithPolicy", Reason: "policy can not be created", Err: errors.New("cause")}
+	if errors.Unwrap(err) != err.Err || err.Error() != "nucleus: WithPolicy: policy can not be created: cause" {
+		t.FailNow()
+	}
+	if PolicyType(0).String() != "unknown" || TinyLfuPolicy.String() != "tinylfu" {
+		t.FailNow()
+	}
+}
+
+func TestNew4(t *testing.T) {
+	// constructors taking the settings as arguments accept negative durations
+	// and report errors without option names
+	cache, err := NewTlruCache(1, -time.Second)
+	if err != nil {
+		t.FailNow()
+	}
+	defer cache.Close()
+	cache.Add(1, "1")
+	if ttl, ok := cache.policy.(*tlru.Tlru[interface{}, interface{}]).TTL(1); !ok || ttl != 0 {
+		t.FailNow()
+	}
+	cache, err = NewTlruCacheWithSweep(1, time.Second, -time.Second)
+	if err != nil || cache.policy.(*tlru.Tlru[interface{}, interface{}]).DaemonStarted() {
+		t.FailNow()
+	}
+	var optionErr *OptionError
+	for _, newCache := range []func(int) (*Cache[interface{}, interface{}], error){NewLruCache, NewTinyLfuCache} {
+		cache, err := newCache(0)
+		if cache != nil || !errors.Is(err, ErrInvalidCapacity) || errors.As(err, &optionErr) {
+			t.FailNow()
+		}
+	}
+	if _, err := NewTlruCache(1, time.Second, WithClock(nil)); !errors.As(err, &optionErr) || optionErr.Option != "WithClock" {
+		t.FailNow()
+	}
+}
+
+// plainPolicy hides the optional interfaces of the embedded policy.
+type plainPolicy[K comparable, V any] struct {
+	Policy[K, V]
+}
+
+func TestNewWithPolicy(t *testing.T) {
+	capacity := 2
+	lruPolicy, _ := lru.New[string, int](capacity)
+	cache, err := NewWithPolicy[string, int](plainPolicy[string, int]{lruPolicy}, WithStats())
+	if err != nil || cache.Cap() != capacity {
+		t.FailNow()
+	}
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	cache.Get("a")
+	cache.Add("c", 3)
+	if !cache.Contains("a") || cache.Contains("b") || cache.Stats().Adds != 3 || cache.Stats().Hits != 1 {
+		t.FailNow()
+	}
+	var optionErr *OptionError
+	if cache, err := NewWithPolicy[string, int](nil); cache != nil || !errors.As(err, &optionErr) || optionErr.Option != "NewWithPolicy" {
+		t.FailNow()
+	}
+	if cache, err := NewWithPolicy[string, int](lruPolicy, WithCapacity(capacity)); cache != nil || !errors.As(err, &optionErr) || optionErr.Option != "WithPolicy" {
+		t.FailNow()
+	}
+}
+
+// clockedLru records the clock given to the policy.
+type clockedLru struct {
+	*lru.Lru[string, int]
+	clock tlru.Clock
+}
+
+func (p *clockedLru) SetClock(clock tlru.Clock) {
+	p.clock = clock
+}
+
+func TestNewWithPolicy2(t *testing.T) {
+	capacity := 2
+	clock := nucleustest.NewFakeClock(time.Now())
+	tlruPolicy, _ := tlru.New[string, int](capacity, time.Minute)
+	expired := make(chan string, 1)
+	cache, err := NewWithPolicy[string, int](tlruPolicy,
+		WithClock(clock),
+		WithSweepInterval(time.Minute),
+		WithEvictionCallback(func(key string, value int, reason EvictReason) {
+			if reason == EvictExpired {
+				expired <- key
+			}
+		}),
+	)
+	if err != nil || !tlruPolicy.DaemonStarted() {
+		t.FailNow()
+	}
+	defer cache.Close()
+	cache.Add("a", 1)
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	select {
+	case key := <-expired:
+		if key != "a" {
+			t.FailNow()
+		}
+	case <-time.After(time.Second):
+		t.FailNow()
+	}
+	lruPolicy, _ := lru.New[string, int](capacity)
+	clockedPolicy := &clockedLru{Lru: lruPolicy}
+	if _, err := NewWithPolicy[string, int](clockedPolicy, WithClock(clock)); err != nil || clockedPolicy.clock != clock {
+		t.FailNow()
+	}
+	tests := []struct {
+		option string
+		policy Policy[string, int]
+		opts   []Option
+	}{
+		{"WithSweepInterval", lruPolicy, []Option{WithSweepInterval(time.Second)}},
+		{"WithSweepInterval", lruPolicy, []Option{WithSweepInterval(-time.Second)}},
+		{"WithSweepInterval", tlruPolicy, []Option{WithSweepInterval(-time.Second)}},
+		{"WithClock", lruPolicy, []Option{WithClock(nil)}},
+	}
+	for _, test := range tests {
+		cache, err := NewWithPolicy[string, int](test.policy, test.opts...)
+		var optionErr *OptionError
+		if cache != nil || !errors.As(err, &optionErr) || optionErr.Option != test.option {
+			t.Fatal(test.option, err)
+		}
+	}
+}
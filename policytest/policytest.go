@@ -0,0 +1,365 @@
+// Package policytest provides conformance tests of nucleus.Policy, which
+// custom policies given to nucleus.NewWithPolicy can run in their tests:
+//
+//	func TestPolicy(t *testing.T) {
+//		policytest.Run(t, func(capacity int) (nucleus.Policy[int, int], error) {
+//			return New[int, int](capacity)
+//		})
+//	}
+package policytest
+
+import (
+	"github.com/SemihBKGR/nucleus"
+	"sync"
+	"testing"
+)
+
+// Factory returns new policy with given capacity, or error unless capacity
+// is positive value.
+type Factory func(capacity int) (nucleus.Policy[int, int], error)
+
+const capacity = 16
+
+// Run runs the conformance tests of the policies created by the factory as
+// subtests of t. Tests of the optional interfaces, such as
+// nucleus.EvictingPolicy, run only if the policies implement them.
+func Run(t *testing.T, factory Factory) {
+	t.Run("New", func(t *testing.T) {
+		testNew(t, factory)
+	})
+	t.Run("AddGet", func(t *testing.T) {
+		testAddGet(t, newPolicy(t, factory))
+	})
+	t.Run("Remove", func(t *testing.T) {
+		testRemove(t, newPolicy(t, factory))
+	})
+	t.Run("Clear", func(t *testing.T) {
+		testClear(t, newPolicy(t, factory))
+	})
+	t.Run("KeysValues", func(t *testing.T) {
+		testKeysValues(t, newPolicy(t, factory))
+	})
+	t.Run("Capacity", func(t *testing.T) {
+		testCapacity(t, newPolicy(t, factory))
+	})
+	t.Run("SetCap", func(t *testing.T) {
+		testSetCap(t, newPolicy(t, factory))
+	})
+	t.Run("Evicting", func(t *testing.T) {
+		testEvicting(t, newPolicy(t, factory))
+	})
+	t.Run("Ordered", func(t *testing.T) {
+		testOrdered(t, newPolicy(t, factory))
+	})
+	t.Run("Weighted", func(t *testing.T) {
+		testWeighted(t, newPolicy(t, factory))
+	})
+	t.Run("Cache", func(t *testing.T) {
+		testCache(t, newPolicy(t, factory))
+	})
+}
+
+func newPolicy(t *testing.T, factory Factory) nucleus.Policy[int, int] {
+	t.Helper()
+	policy, err := factory(capacity)
+	if policy == nil || err != nil {
+		t.Fatalf("factory(%d) = %v, %v", capacity, policy, err)
+	}
+	stopDaemon(t, policy)
+	return policy
+}
+
+// stopDaemon stops daemon of the policy, if any, at the end of the test.
+func stopDaemon(t *testing.T, policy nucleus.Policy[int, int]) {
+	if daemonPolicy, ok := policy.(nucleus.DaemonPolicy); ok {
+		t.Cleanup(func() {
+			daemonPolicy.StopDaemon()
+		})
+	}
+}
+
+func testNew(t *testing.T, factory Factory) {
+	for _, invalid := range []int{0, -1} {
+		if _, err := factory(invalid); err == nil {
+			t.Errorf("factory(%d) returned no error", invalid)
+		}
+	}
+	policy := newPolicy(t, factory)
+	if policy.Cap() != capacity {
+		t.Errorf("Cap() = %d, want %d", policy.Cap(), capacity)
+	}
+	if policy.Len() != 0 {
+		t.Errorf("Len() = %d of new policy", policy.Len())
+	}
+}
+
+func testAddGet(t *testing.T, policy nucleus.Policy[int, int]) {
+	if _, ok := policy.Get(1, true); ok {
+		t.Fatal("Get returned absent key")
+	}
+	if policy.Add(1, 1) {
+		t.Error("Add reported eviction in empty policy")
+	}
+	if value, ok := policy.Get(1, true); !ok || value != 1 {
+		t.Fatalf("Get(1) = %d, %t, want 1, true", value, ok)
+	}
+	policy.Add(1, 2)
+	if value, ok := policy.Get(1, false); !ok || value != 2 {
+		t.Fatalf("Get(1) = %d, %t after update, want 2, true", value, ok)
+	}
+	if policy.Len() != 1 {
+		t.Errorf("Len() = %d after update, want 1", policy.Len())
+	}
+}
+
+func testRemove(t *testing.T, policy nucleus.Policy[int, int]) {
+	policy.Add(1, 1)
+	policy.Add(2, 2)
+	if !policy.Remove(1) {
+		t.Fatal("Remove(1) = false, want true")
+	}
+	if policy.Remove(1) {
+		t.Fatal("Remove(1) = true for removed key")
+	}
+	if _, ok := policy.Get(1, false); ok {
+		t.Fatal("Get returned removed key")
+	}
+	if value, ok := policy.Get(2, false); !ok || value != 2 || policy.Len() != 1 {
+		t.Fatal("Remove affected other entries")
+	}
+}
+
+func testClear(t *testing.T, policy nucleus.Policy[int, int]) {
+	for i := 0; i < capacity/2; i++ {
+		policy.Add(i, i)
+	}
+	length := policy.Len()
+	if cleared := policy.Clear(); cleared != length {
+		t.Errorf("Clear() = %d, want %d", cleared, length)
+	}
+	if policy.Len() != 0 || len(policy.Keys()) != 0 {
+		t.Fatal("Clear left entries")
+	}
+	if _, ok := policy.Get(0, false); ok {
+		t.Fatal("Get returned cleared key")
+	}
+	policy.Add(0, 0)
+	if value, ok := policy.Get(0, false); !ok || value != 0 {
+		t.Fatal("Add failed after Clear")
+	}
+}
+
+func testKeysValues(t *testing.T, policy nucleus.Policy[int, int]) {
+	for i := 0; i < capacity*2; i++ {
+		policy.Add(i, i*10)
+	}
+	keys := policy.Keys()
+	values := policy.Values()
+	if len(keys) != policy.Len() || len(values) != policy.Len() {
+		t.Fatalf("len(Keys()) = %d, len(Values()) = %d, want Len() = %d", len(keys), len(values), policy.Len())
+	}
+	remaining := make(map[int]int)
+	for _, value := range values {
+		remaining[value]++
+	}
+	for _, key := range keys {
+		value, ok := policy.Get(key, false)
+		if !ok || value != key*10 {
+			t.Fatalf("Get(%d) = %d, %t for key returned by Keys", key, value, ok)
+		}
+		if remaining[value] == 0 {
+			t.Fatalf("Values does not match Keys")
+		}
+		remaining[value]--
+	}
+}
+
+func testCapacity(t *testing.T, policy nucleus.Policy[int, int]) {
+	for i := 0; i < capacity*4; i++ {
+		policy.Add(i, i)
+		policy.Get(i%(capacity/2), true)
+		if policy.Len() > policy.Cap() {
+			t.Fatalf("Len() = %d exceeds Cap() = %d", policy.Len(), policy.Cap())
+		}
+	}
+	for i := capacity * 4; i < capacity*5; i++ {
+		full := policy.Len() == policy.Cap()
+		if eviction := policy.Add(i, i); full && !eviction {
+			t.Fatalf("Add(%d) to full policy reported no eviction", i)
+		}
+	}
+}
+
+func testSetCap(t *testing.T, policy nucleus.Policy[int, int]) {
+	for i := 0; i < capacity; i++ {
+		policy.Add(i, i)
+	}
+	if err := policy.SetCap(capacity / 2); err != nil || policy.Cap() != capacity/2 {
+		t.Fatalf("SetCap(%d) = %v, Cap() = %d", capacity/2, err, policy.Cap())
+	}
+	if policy.Len() > capacity/2 {
+		t.Fatalf("Len() = %d exceeds decreased capacity", policy.Len())
+	}
+	if err := policy.SetCap(0); err == nil {
+		t.Error("SetCap(0) returned no error")
+	}
+	if err := policy.SetCap(capacity * 2); err != nil || policy.Cap() != capacity*2 {
+		t.Fatalf("SetCap(%d) = %v, Cap() = %d", capacity*2, err, policy.Cap())
+	}
+	for i := 0; i < capacity*2; i++ {
+		policy.Add(i, i)
+	}
+	if policy.Len() > capacity*2 {
+		t.Fatalf("Len() = %d exceeds increased capacity", policy.Len())
+	}
+}
+
+// testEvicting tests that the evicted entries are reported exactly, that is,
+// the entries added and neither evicted nor removed are the entries left.
+func testEvicting(t *testing.T, policy nucleus.Policy[int, int]) {
+	evictingPolicy, ok := policy.(nucleus.EvictingPolicy[int, int])
+	if !ok {
+		t.Skip("policy is not an EvictingPolicy")
+	}
+	present := make(map[int]bool)
+	evictingPolicy.OnEvict(func(key int, value int) {
+		if !present[key] || key != value {
+			t.Fatalf("OnEvict(%d, %d) for entry not in policy", key, value)
+		}
+		delete(present, key)
+	})
+	for i := 0; i < capacity*4; i++ {
+		key := i % (capacity * 2)
+		present[key] = true
+		policy.Add(key, key)
+		policy.Get(i%(capacity/2), true)
+		if i%5 == 0 && policy.Remove(key-1) {
+			delete(present, key-1)
+		}
+	}
+	policy.SetCap(capacity / 2)
+	if policy.Len() != len(present) {
+		t.Fatalf("Len() = %d, want %d entries added but not evicted or removed", policy.Len(), len(present))
+	}
+	for _, key := range policy.Keys() {
+		if !present[key] {
+			t.Fatalf("evicted or removed key %d is in policy", key)
+		}
+	}
+}
+
+func testOrdered(t *testing.T, policy nucleus.Policy[int, int]) {
+	orderedPolicy, ok := policy.(nucleus.OrderedPolicy[int, int])
+	if !ok {
+		t.Skip("policy is not an OrderedPolicy")
+	}
+	if _, _, ok := orderedPolicy.Oldest(); ok {
+		t.Fatal("Oldest returned entry of empty policy")
+	}
+	if _, _, ok := orderedPolicy.Newest(); ok {
+		t.Fatal("Newest returned entry of empty policy")
+	}
+	for i := 0; i < capacity*2; i++ {
+		policy.Add(i, i)
+		policy.Get(i/2, true)
+	}
+	keys := make([]int, 0, policy.Len())
+	orderedPolicy.Range(func(key int, value int) bool {
+		if key != value {
+			t.Fatalf("Range called with %d, %d", key, value)
+		}
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != policy.Len() {
+		t.Fatalf("Range visited %d entries, want Len() = %d", len(keys), policy.Len())
+	}
+	if key, _, ok := orderedPolicy.Oldest(); !ok || key != keys[0] {
+		t.Errorf("Oldest() = %d, %t, want first key of Range %d", key, ok, keys[0])
+	}
+	if key, _, ok := orderedPolicy.Newest(); !ok || key != keys[len(keys)-1] {
+		t.Errorf("Newest() = %d, %t, want last key of Range %d", key, ok, keys[len(keys)-1])
+	}
+	visited := 0
+	orderedPolicy.Range(func(key int, value int) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Errorf("Range visited %d entries after fn returned false", visited)
+	}
+}
+
+func testWeighted(t *testing.T, policy nucleus.Policy[int, int]) {
+	weightedPolicy, ok := policy.(nucleus.WeightedPolicy[int, int])
+	if !ok {
+		t.Skip("policy is not a WeightedPolicy")
+	}
+	if weightedPolicy.MaxCost() != 0 {
+		t.Fatalf("MaxCost() = %d of new policy, want 0", weightedPolicy.MaxCost())
+	}
+	for i := 0; i < capacity; i++ {
+		weightedPolicy.AddWithCost(i, i, 2)
+	}
+	if weightedPolicy.Cost() != int64(policy.Len())*2 {
+		t.Fatalf("Cost() = %d, want %d", weightedPolicy.Cost(), policy.Len()*2)
+	}
+	maxCost := int64(capacity / 2)
+	if err := weightedPolicy.SetMaxCost(maxCost); err != nil || weightedPolicy.Cost() > maxCost {
+		t.Fatalf("SetMaxCost(%d) = %v, Cost() = %d", maxCost, err, weightedPolicy.Cost())
+	}
+	if err := weightedPolicy.SetMaxCost(-1); err == nil {
+		t.Error("SetMaxCost(-1) returned no error")
+	}
+	weightedPolicy.SetWeigher(func(key int, value int) int64 {
+		return 3
+	})
+	for i := 0; i < capacity; i++ {
+		policy.Add(i, i)
+		if weightedPolicy.Cost() > maxCost {
+			t.Fatalf("Cost() = %d exceeds max cost %d", weightedPolicy.Cost(), maxCost)
+		}
+	}
+	if weightedPolicy.Cost() != int64(policy.Len())*3 {
+		t.Fatalf("Cost() = %d, want %d by the weigher", weightedPolicy.Cost(), policy.Len()*3)
+	}
+	weightedPolicy.AddWithCost(capacity, capacity, maxCost+1)
+	if _, ok := policy.Get(capacity, false); ok {
+		t.Error("entry whose cost exceeds max cost is added")
+	}
+}
+
+// testCache tests the policy under concurrent use by nucleus.Cache, so that
+// races are detected if the test is run with -race.
+func testCache(t *testing.T, policy nucleus.Policy[int, int]) {
+	cache, err := nucleus.NewWithPolicy[int, int](policy, nucleus.WithStats())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	wg := sync.WaitGroup{}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := (g*500 + i) % (capacity * 2)
+				cache.Add(key, key)
+				if value, ok := cache.Get(key); ok && value != key {
+					t.Errorf("Get(%d) = %d", key, value)
+				}
+				if i%7 == 0 {
+					cache.Remove(key)
+				}
+				cache.Keys()
+			}
+		}(g)
+	}
+	wg.Wait()
+	if cache.Len() > cache.Cap() {
+		t.Fatalf("Len() = %d exceeds Cap() = %d", cache.Len(), cache.Cap())
+	}
+	if stats := cache.Stats(); stats.Adds+stats.Updates != 2000 || stats.Requests() != 2000 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
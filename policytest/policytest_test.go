@@ -0,0 +1,151 @@
+package policytest
+
+import (
+	"github.com/SemihBKGR/nucleus"
+	"github.com/SemihBKGR/nucleus/arc"
+	"github.com/SemihBKGR/nucleus/clock"
+	"github.com/SemihBKGR/nucleus/clockpro"
+	"github.com/SemihBKGR/nucleus/fifo"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+	"github.com/SemihBKGR/nucleus/lfu"
+	"github.com/SemihBKGR/nucleus/lru"
+	"github.com/SemihBKGR/nucleus/mru"
+	"github.com/SemihBKGR/nucleus/policyutil"
+	"github.com/SemihBKGR/nucleus/slru"
+	"github.com/SemihBKGR/nucleus/tinylfu"
+	"github.com/SemihBKGR/nucleus/tlru"
+	"github.com/SemihBKGR/nucleus/twoq"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	factories := map[string]Factory{
+		"lru": func(capacity int) (nucleus.Policy[int, int], error) {
+			return lru.New[int, int](capacity)
+		},
+		"mru": func(capacity int) (nucleus.Policy[int, int], error) {
+			return mru.New[int, int](capacity)
+		},
+		"fifo": func(capacity int) (nucleus.Policy[int, int], error) {
+			return fifo.New[int, int](capacity)
+		},
+		"tlru": func(capacity int) (nucleus.Policy[int, int], error) {
+			return tlru.New[int, int](capacity, time.Hour)
+		},
+		"lfu": func(capacity int) (nucleus.Policy[int, int], error) {
+			return lfu.New[int, int](capacity)
+		},
+		"arc": func(capacity int) (nucleus.Policy[int, int], error) {
+			return arc.New[int, int](capacity)
+		},
+		"tinylfu": func(capacity int) (nucleus.Policy[int, int], error) {
+			return tinylfu.New[int, int](capacity)
+		},
+		"twoq": func(capacity int) (nucleus.Policy[int, int], error) {
+			return twoq.New[int, int](capacity, twoq.DefaultRecentRatio, twoq.DefaultGhostRatio)
+		},
+		"slru": func(capacity int) (nucleus.Policy[int, int], error) {
+			return slru.New[int, int](capacity, slru.DefaultProtectedRatio)
+		},
+		"clock": func(capacity int) (nucleus.Policy[int, int], error) {
+			return clock.New[int, int](capacity)
+		},
+		"clockpro": func(capacity int) (nucleus.Policy[int, int], error) {
+			return clockpro.New[int, int](capacity)
+		},
+		"lifo": func(capacity int) (nucleus.Policy[int, int], error) {
+			return newLifo[int, int](capacity)
+		},
+	}
+	for name, factory := range factories {
+		t.Run(name, func(t *testing.T) {
+			Run(t, factory)
+		})
+	}
+}
+
+// lifo is a custom policy evicting the most recently added entry, built on
+// policyutil.List as third party policies would be.
+type lifo[K comparable, V any] struct {
+	capacity     int
+	entries      *policyutil.List[K, V]
+	evictHandler func(key K, value V)
+}
+
+func newLifo[K comparable, V any](capacity int) (*lifo[K, V], error) {
+	if capacity <= 0 {
+		return nil, errs.ErrNonPositiveCapacity
+	}
+	return &lifo[K, V]{capacity: capacity, entries: policyutil.NewList[K, V]()}, nil
+}
+
+func (l *lifo[K, V]) Add(key K, value V) (eviction bool) {
+	l.Remove(key)
+	for l.entries.Len() >= l.capacity {
+		l.evict()
+		eviction = true
+	}
+	l.entries.PushFront(key, value, 1)
+	return
+}
+
+func (l *lifo[K, V]) Get(key K, _ bool) (value V, ok bool) {
+	if entry, ok := l.entries.Get(key); ok {
+		return entry.Value, true
+	}
+	return
+}
+
+func (l *lifo[K, V]) Remove(key K) bool {
+	entry, ok := l.entries.Get(key)
+	if ok {
+		l.entries.Remove(entry)
+	}
+	return ok
+}
+
+func (l *lifo[K, V]) OnEvict(handler func(key K, value V)) {
+	l.evictHandler = handler
+}
+
+func (l *lifo[K, V]) evict() {
+	entry := l.entries.Front()
+	l.entries.Remove(entry)
+	if l.evictHandler != nil {
+		l.evictHandler(entry.Key, entry.Value)
+	}
+}
+
+func (l *lifo[K, V]) Clear() int {
+	length := l.entries.Len()
+	l.entries.Clear()
+	return length
+}
+
+func (l *lifo[K, V]) Len() int {
+	return l.entries.Len()
+}
+
+func (l *lifo[K, V]) Cap() int {
+	return l.capacity
+}
+
+func (l *lifo[K, V]) SetCap(newCapacity int) error {
+	if newCapacity <= 0 {
+		return errs.ErrNonPositiveCapacity
+	}
+	for l.entries.Len() > newCapacity {
+		l.evict()
+	}
+	l.capacity = newCapacity
+	return nil
+}
+
+func (l *lifo[K, V]) Keys() []K {
+	return l.entries.Keys()
+}
+
+func (l *lifo[K, V]) Values() []V {
+	return l.entries.Values()
+}
@@ -0,0 +1,167 @@
+// Package policyutil provides building blocks of policies, such as the
+// ordered map used as eviction list by lru, mru, fifo and tlru, so that
+// custom policies given to nucleus.NewWithPolicy need not rebuild them.
+package policyutil
+
+// Entry is an entry of List.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+	// Cost is counted in the total cost of the list.
+	Cost       int64
+	prev, next *Entry[K, V]
+	list       *List[K, V]
+}
+
+// Next returns the entry pushed or moved to the front before e, nil if e is
+// the back entry.
+func (e *Entry[K, V]) Next() *Entry[K, V] {
+	if e.list != nil && e.next != &e.list.root {
+		return e.next
+	}
+	return nil
+}
+
+// Prev returns the entry pushed or moved to the front after e, nil if e is
+// the front entry.
+func (e *Entry[K, V]) Prev() *Entry[K, V] {
+	if e.list != nil && e.prev != &e.list.root {
+		return e.prev
+	}
+	return nil
+}
+
+// List maps keys to entries which are linked in the order they are pushed
+// or moved to the front. Unlike container/list, links are kept in the
+// entries, so an entry is a single allocation whose value needs no type
+// assertion.
+type List[K comparable, V any] struct {
+	root    Entry[K, V]
+	entries map[K]*Entry[K, V]
+	cost    int64
+}
+
+// NewList returns new empty list.
+func NewList[K comparable, V any]() *List[K, V] {
+	l := &List[K, V]{
+		entries: make(map[K]*Entry[K, V]),
+	}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	return l
+}
+
+// Len returns count of the entries.
+func (l *List[K, V]) Len() int {
+	return len(l.entries)
+}
+
+// Cost returns total cost of the entries.
+func (l *List[K, V]) Cost() int64 {
+	return l.cost
+}
+
+// Get returns entry of the key.
+func (l *List[K, V]) Get(key K) (entry *Entry[K, V], ok bool) {
+	entry, ok = l.entries[key]
+	return
+}
+
+// Front returns the most recently pushed or moved entry, nil if the list is
+// empty.
+func (l *List[K, V]) Front() *Entry[K, V] {
+	if len(l.entries) == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back returns the least recently pushed or moved entry, nil if the list is
+// empty.
+func (l *List[K, V]) Back() *Entry[K, V] {
+	if len(l.entries) == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// PushFront adds entry at the front, replacing the entry of the key if any.
+func (l *List[K, V]) PushFront(key K, value V, cost int64) *Entry[K, V] {
+	if entry, ok := l.entries[key]; ok {
+		l.Remove(entry)
+	}
+	entry := &Entry[K, V]{
+		Key:   key,
+		Value: value,
+		Cost:  cost,
+	}
+	l.insert(entry, &l.root)
+	l.entries[key] = entry
+	l.cost += cost
+	return entry
+}
+
+// MoveToFront moves the entry to the front.
+func (l *List[K, V]) MoveToFront(entry *Entry[K, V]) {
+	if entry.list != l || l.root.next == entry {
+		return
+	}
+	l.unlink(entry)
+	l.insert(entry, &l.root)
+}
+
+// Remove removes the entry.
+func (l *List[K, V]) Remove(entry *Entry[K, V]) {
+	if entry.list != l {
+		return
+	}
+	l.unlink(entry)
+	delete(l.entries, entry.Key)
+	l.cost -= entry.Cost
+}
+
+// Clear removes all entries.
+func (l *List[K, V]) Clear() {
+	for entry := l.root.next; entry != &l.root; {
+		next := entry.next
+		entry.prev, entry.next, entry.list = nil, nil, nil
+		entry = next
+	}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	clear(l.entries)
+	l.cost = 0
+}
+
+// Keys returns keys of the entries from the front to the back.
+func (l *List[K, V]) Keys() []K {
+	keys := make([]K, 0, len(l.entries))
+	for entry := l.Front(); entry != nil; entry = entry.Next() {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+// Values returns values of the entries from the front to the back.
+func (l *List[K, V]) Values() []V {
+	values := make([]V, 0, len(l.entries))
+	for entry := l.Front(); entry != nil; entry = entry.Next() {
+		values = append(values, entry.Value)
+	}
+	return values
+}
+
+// insert links the entry after at.
+func (l *List[K, V]) insert(entry, at *Entry[K, V]) {
+	entry.prev = at
+	entry.next = at.next
+	entry.prev.next = entry
+	entry.next.prev = entry
+	entry.list = l
+}
+
+func (l *List[K, V]) unlink(entry *Entry[K, V]) {
+	entry.prev.next = entry.next
+	entry.next.prev = entry.prev
+	entry.prev, entry.next, entry.list = nil, nil, nil
+}
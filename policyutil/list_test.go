@@ -0,0 +1,50 @@
+package policyutil
+
+import "testing"
+
+func TestList(t *testing.T) {
+	l := NewList[int, string]()
+	if l.Front() != nil || l.Back() != nil || l.Len() != 0 {
+		t.FailNow()
+	}
+	for i := 0; i < 5; i++ {
+		l.PushFront(i, "", int64(i))
+	}
+	if l.Len() != 5 || l.Cost() != 10 || l.Front().Key != 4 || l.Back().Key != 0 {
+		t.FailNow()
+	}
+	entry, ok := l.Get(2)
+	if !ok || entry.Key != 2 || entry.Next().Key != 1 || entry.Prev().Key != 3 {
+		t.FailNow()
+	}
+	l.MoveToFront(entry)
+	l.MoveToFront(entry)
+	if keys := l.Keys(); len(keys) != 5 || keys[0] != 2 || keys[1] != 4 || keys[4] != 0 {
+		t.FailNow()
+	}
+	if l.Front().Prev() != nil || l.Back().Next() != nil {
+		t.FailNow()
+	}
+	l.Remove(entry)
+	l.Remove(entry)
+	if _, ok := l.Get(2); ok || l.Len() != 4 || l.Cost() != 8 || entry.Next() != nil {
+		t.FailNow()
+	}
+	// pushing an existing key replaces its entry
+	l.PushFront(0, "zero", 10)
+	if l.Len() != 4 || l.Cost() != 18 || l.Front().Value != "zero" || l.Back().Key != 1 {
+		t.FailNow()
+	}
+	if values := l.Values(); len(values) != 4 || values[0] != "zero" {
+		t.FailNow()
+	}
+	back := l.Back()
+	l.Clear()
+	if l.Len() != 0 || l.Cost() != 0 || l.Front() != nil || back.Next() != nil {
+		t.FailNow()
+	}
+	l.MoveToFront(back)
+	if l.Len() != 0 || l.Front() != nil {
+		t.FailNow()
+	}
+}
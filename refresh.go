@@ -0,0 +1,165 @@
+package nucleus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// agedPolicy is implemented by policies tracking when entries are written,
+// such as tlru.
+type agedPolicy[K comparable] interface {
+	Age(key K) (age time.Duration, ok bool)
+}
+
+// refresher reloads entries older than refreshAfter in the background.
+// Keys are queued once until their reload completes, and reloaded by a
+// fixed number of workers.
+type refresher[K comparable, V any] struct {
+	cache        *Cache[K, V]
+	policy       agedPolicy[K]
+	refreshAfter time.Duration
+	loader       LoaderFunc[K, V]
+	lock         sync.Mutex
+	pending      map[K]struct{}
+	queue        chan K
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// SetRefresh enables refresh-ahead: Get keeps returning entries older than
+// refreshAfter, and queues their reload by loader which runs on one of the
+// workers. Reloaded values replace the entries keeping their ttl, which
+// restarts, unless the entries are removed in the meantime. Failed reloads keep the
+// old value, and are retried by the next Get after the negative ttl set by
+// SetNegativeTTL. Keys are not queued while all workers are busy and the
+// queue is full. Zero refreshAfter disables refreshing.
+// Returns error if refreshAfter is negative value, workers is not positive
+// value, loader is nil or policy does not track age of the entries, and
+// ErrClosed if the cache is closed.
+func (c *Cache[K, V]) SetRefresh(refreshAfter time.Duration, workers int, loader LoaderFunc[K, V]) error {
+	var r *refresher[K, V]
+	if refreshAfter < 0 {
+		return fmt.Errorf("%w: refresh duration must not be negative value", ErrInvalidTTL)
+	}
+	if refreshAfter > 0 {
+		policy, ok := c.policy.(agedPolicy[K])
+		if !ok {
+			return unsupported("refresh")
+		}
+		if workers <= 0 {
+			return fmt.Errorf("%w: worker count must be positive value", ErrInvalidArgument)
+		}
+		if loader == nil {
+			return fmt.Errorf("%w: loader must not be nil", ErrInvalidArgument)
+		}
+		r = &refresher[K, V]{
+			cache:        c,
+			policy:       policy,
+			refreshAfter: refreshAfter,
+			loader:       loader,
+			pending:      make(map[K]struct{}),
+			queue:        make(chan K, workers),
+		}
+		r.start(workers)
+	}
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		r.stop()
+		return ErrClosed
+	}
+	old := c.refresher
+	c.refresher = r
+	c.lock.Unlock()
+	old.stop()
+	return nil
+}
+
+// SetRefresh enables refresh-ahead in every shard, each running its own
+// workers.
+func (s *ShardedCache[K, V]) SetRefresh(refreshAfter time.Duration, workers int, loader LoaderFunc[K, V]) error {
+	for _, shard := range s.shards {
+		if err := shard.SetRefresh(refreshAfter, workers, loader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *refresher[K, V]) start(workers int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer r.wg.Done()
+			for {
+				select {
+				case key := <-r.queue:
+					r.refresh(ctx, key)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
+
+// stop cancels running reloads and waits for the workers to exit.
+func (r *refresher[K, V]) stop() {
+	if r == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+// check queues reload of the key if it is old enough, the cache must be
+// locked by the caller.
+func (r *refresher[K, V]) check(key K) {
+	if age, ok := r.policy.Age(key); !ok || age < r.refreshAfter {
+		return
+	}
+	if r.cache.loadGroup.failed(key) {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.pending[key]; ok {
+		return
+	}
+	select {
+	case r.queue <- key:
+		r.pending[key] = struct{}{}
+	default:
+	}
+}
+
+func (r *refresher[K, V]) refresh(ctx context.Context, key K) {
+	defer func() {
+		r.lock.Lock()
+		delete(r.pending, key)
+		r.lock.Unlock()
+	}()
+	c := r.cache
+	start := time.Now()
+	value, err := r.loader(ctx, key)
+	c.stats.recordLoad(err, time.Since(start))
+	if err != nil {
+		g := &c.loadGroup
+		g.lock.Lock()
+		if g.negativeTTL > 0 {
+			g.addFailure(key, &LoadError{Key: key, Err: err})
+		}
+		g.lock.Unlock()
+		return
+	}
+	c.lock.Lock()
+	defer c.unlockAsync()
+	if old, ok := c.policy.Get(key, false); ok {
+		c.policy.Add(key, value)
+		c.recordPut(key, old, true)
+	}
+}
@@ -0,0 +1,116 @@
+package nucleus
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_SetRefresh(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCacheWithSweep(capacity, time.Hour, 0)
+	defer cache.Close()
+	calls := int32(0)
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "new", nil
+	}
+	if cache.SetRefresh(20*time.Millisecond, 2, loader) != nil {
+		t.FailNow()
+	}
+	cache.Add(1, "old")
+	if value, _ := cache.Get(1); value != "old" || atomic.LoadInt32(&calls) != 0 {
+		t.FailNow()
+	}
+	time.Sleep(30 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		if value, ok := cache.Get(1); !ok || value != "old" {
+			t.FailNow()
+		}
+	}
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for value, _ := cache.Get(1); value != "new"; value, _ = cache.Get(1) {
+		if time.Now().After(deadline) {
+			t.FailNow()
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.FailNow()
+	}
+	stats := cache.Stats()
+	if stats.LoadSuccesses != 1 || stats.Updates != 1 {
+		t.FailNow()
+	}
+}
+
+func TestCache_SetRefresh2(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCacheWithSweep(capacity, time.Hour, 0)
+	defer cache.Close()
+	calls := int32(0)
+	loader := func(ctx context.Context, key interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("refresh failed")
+	}
+	_ = cache.SetNegativeTTL(time.Hour)
+	_ = cache.SetRefresh(time.Millisecond, 1, loader)
+	cache.Add(1, "old")
+	time.Sleep(2 * time.Millisecond)
+	cache.Get(1)
+	deadline := time.Now().Add(time.Second)
+	for cache.Stats().LoadFailures == 0 {
+		if time.Now().After(deadline) {
+			t.FailNow()
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if value, ok := cache.Get(1); !ok || value != "old" {
+		t.FailNow()
+	}
+	time.Sleep(5 * time.Millisecond)
+	if atomic.LoadInt32(&calls) != 1 {
+		t.FailNow()
+	}
+}
+
+func TestCache_SetRefresh3(t *testing.T) {
+	capacity := 10
+	loader := func(ctx context.Context, key interface{}) (interface{}, error) {
+		return key, nil
+	}
+	lruCache, _ := NewLruCache(capacity)
+	if lruCache.SetRefresh(time.Second, 1, loader) == nil || lruCache.SetRefresh(0, 0, nil) != nil {
+		t.FailNow()
+	}
+	goroutines := runtime.NumGoroutine()
+	cache, _ := NewTlruCacheWithSweep(capacity, time.Hour, 0)
+	if cache.SetRefresh(-time.Second, 1, loader) == nil ||
+		cache.SetRefresh(time.Second, 0, loader) == nil ||
+		cache.SetRefresh(time.Second, 1, nil) == nil {
+		t.FailNow()
+	}
+	if cache.SetRefresh(time.Second, 4, loader) != nil || runtime.NumGoroutine() != goroutines+4 {
+		t.FailNow()
+	}
+	if cache.SetRefresh(time.Second, 2, loader) != nil || runtime.NumGoroutine() != goroutines+2 {
+		t.FailNow()
+	}
+	if cache.Close() != nil || runtime.NumGoroutine() != goroutines {
+		t.FailNow()
+	}
+	sharded, _ := NewShardedCache(2, capacity, func(cap int) (*Cache[interface{}, interface{}], error) {
+		return NewTlruCacheWithSweep(cap, time.Hour, 0)
+	})
+	if sharded.SetRefresh(time.Second, 1, loader) != nil || runtime.NumGoroutine() != goroutines+2 {
+		t.FailNow()
+	}
+	sharded.Close()
+}
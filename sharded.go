@@ -0,0 +1,166 @@
+package nucleus
+
+import (
+	"fmt"
+	"github.com/SemihBKGR/nucleus/internal/keyhash"
+	"time"
+)
+
+// ShardedCache spreads entries across independent caches by key hash.
+// Every shard has its own policy and lock, so operations on keys of
+// different shards do not contend.
+type ShardedCache[K comparable, V any] struct {
+	shards []*Cache[K, V]
+	hasher keyhash.Hasher[K]
+}
+
+// NewSharded returns new typed sharded cache.
+// Capacity is distributed across shards, each shard is created by newCache
+// with its own share, e.g. NewSharded(16, 1024, NewLru[string, int]).
+func NewSharded[K comparable, V any](shardCount, cap int, newCache func(cap int) (*Cache[K, V], error)) (*ShardedCache[K, V], error) {
+	if shardCount <= 0 {
+		return nil, fmt.Errorf("%w: shard count must be positive value", ErrInvalidArgument)
+	}
+	if cap < shardCount {
+		return nil, fmt.Errorf("%w: must not be less than shard count", ErrInvalidCapacity)
+	}
+	sharded := &ShardedCache[K, V]{
+		shards: make([]*Cache[K, V], shardCount),
+		hasher: keyhash.New[K](),
+	}
+	for i := range sharded.shards {
+		shard, err := newCache(shardCap(cap, shardCount, i))
+		if err != nil {
+			sharded.Close()
+			return nil, err
+		}
+		sharded.shards[i] = shard
+	}
+	return sharded, nil
+}
+
+// NewShardedCache returns new sharded cache,
+// e.g. NewShardedCache(16, 1024, NewLruCache).
+func NewShardedCache(shardCount, cap int, newCache func(cap int) (*Cache[interface{}, interface{}], error)) (*ShardedCache[interface{}, interface{}], error) {
+	return NewSharded[interface{}, interface{}](shardCount, cap, newCache)
+}
+
+// Add adds entry in cache
+func (s *ShardedCache[K, V]) Add(key K, value V) (eviction bool) {
+	return s.shard(key).Add(key, value)
+}
+
+// AddWithTTL adds entry in cache which expires after given ttl.
+// Returns error if ttl is negative value or policy is not an ExpirablePolicy.
+func (s *ShardedCache[K, V]) AddWithTTL(key K, value V, ttl time.Duration) (eviction bool, err error) {
+	return s.shard(key).AddWithTTL(key, value, ttl)
+}
+
+// Set updates cache entry.
+// Returns true if value updated.
+func (s *ShardedCache[K, V]) Set(key K, value V) (ok bool) {
+	return s.shard(key).Set(key, value)
+}
+
+// Get returns value of cached entry.
+func (s *ShardedCache[K, V]) Get(key K) (value V, ok bool) {
+	return s.shard(key).Get(key)
+}
+
+// Remove removes cache entry.
+func (s *ShardedCache[K, V]) Remove(key K) (ok bool) {
+	return s.shard(key).Remove(key)
+}
+
+// Contains returns true if there is a cache entry given given key.
+func (s *ShardedCache[K, V]) Contains(key K) (ok bool) {
+	return s.shard(key).Contains(key)
+}
+
+// Clear removes all entries in the cache.
+// Shards are cleared one by one.
+func (s *ShardedCache[K, V]) Clear() (length int) {
+	for _, shard := range s.shards {
+		length += shard.Clear()
+	}
+	return
+}
+
+// Len returns length of the cache.
+// Shards are counted one by one, so the result is not a consistent snapshot
+// under concurrent writes.
+func (s *ShardedCache[K, V]) Len() (len int) {
+	for _, shard := range s.shards {
+		len += shard.Len()
+	}
+	return
+}
+
+// Cap returns capacity of the cache.
+func (s *ShardedCache[K, V]) Cap() (cap int) {
+	for _, shard := range s.shards {
+		cap += shard.Cap()
+	}
+	return
+}
+
+// SetCap set capacity of the cache by distributing it across shards.
+// Returns error if newCap is less than shard count.
+func (s *ShardedCache[K, V]) SetCap(newCap int) error {
+	if newCap < len(s.shards) {
+		return fmt.Errorf("%w: must not be less than shard count", ErrInvalidCapacity)
+	}
+	for i, shard := range s.shards {
+		if err := shard.SetCap(shardCap(newCap, len(s.shards), i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keys returns a slice of entry keys in the cache.
+func (s *ShardedCache[K, V]) Keys() []K {
+	keys := make([]K, 0)
+	for _, shard := range s.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+// Values returns a slice of entry values in the cache.
+func (s *ShardedCache[K, V]) Values() []V {
+	values := make([]V, 0)
+	for _, shard := range s.shards {
+		values = append(values, shard.Values()...)
+	}
+	return values
+}
+
+// Close closes all shards.
+func (s *ShardedCache[K, V]) Close() error {
+	for _, shard := range s.shards {
+		if shard != nil {
+			_ = shard.Close()
+		}
+	}
+	return nil
+}
+
+// ShardCount returns count of shards.
+func (s *ShardedCache[K, V]) ShardCount() int {
+	return len(s.shards)
+}
+
+func (s *ShardedCache[K, V]) shard(key K) *Cache[K, V] {
+	return s.shards[s.hasher.Sum(key)%uint64(len(s.shards))]
+}
+
+// shardCap returns capacity of the i-th shard, the remainder is given to
+// the first shards.
+func shardCap(cap, shardCount, i int) int {
+	shardCap := cap / shardCount
+	if i < cap%shardCount {
+		shardCap++
+	}
+	return shardCap
+}
@@ -0,0 +1,209 @@
+package nucleus
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewSharded(t *testing.T) {
+	sharded, err := NewSharded(4, 10, NewLru[string, int])
+	if sharded == nil || err != nil {
+		t.FailNow()
+	}
+	if sharded.ShardCount() != 4 || sharded.Cap() != 10 {
+		t.FailNow()
+	}
+	for i, expected := range []int{3, 3, 2, 2} {
+		if sharded.shards[i].Cap() != expected {
+			t.FailNow()
+		}
+	}
+	sharded.Add("1", 1)
+	if value, ok := sharded.Get("1"); !ok || value != 1 {
+		t.FailNow()
+	}
+	for _, args := range [][2]int{{0, 10}, {4, 3}} {
+		sharded, err = NewSharded(args[0], args[1], NewLru[string, int])
+		if sharded != nil || err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestNewShardedCache(t *testing.T) {
+	capacity := 64
+	for _, newCache := range []func(int) (*Cache[interface{}, interface{}], error){
+		NewLruCache, NewMruCache, NewFifoCache, NewLfuCache, NewArcCache,
+		NewTinyLfuCache, NewClockCache, NewClockProCache,
+	} {
+		sharded, err := NewShardedCache(8, capacity, newCache)
+		if sharded == nil || err != nil {
+			t.FailNow()
+		}
+		for i := 0; i < capacity*2; i++ {
+			sharded.Add(i, strconv.Itoa(i))
+		}
+		if sharded.Len() > capacity {
+			t.FailNow()
+		}
+	}
+}
+
+func TestShardedCache_AddWithTTL(t *testing.T) {
+	sharded, _ := NewShardedCache(4, 16, func(cap int) (*Cache[interface{}, interface{}], error) {
+		return NewTlruCache(cap, 0)
+	})
+	defer sharded.Close()
+	_, err := sharded.AddWithTTL(1, "1", 10*time.Millisecond)
+	if err != nil || !sharded.Contains(1) {
+		t.FailNow()
+	}
+	time.Sleep(20 * time.Millisecond)
+	if sharded.Contains(1) {
+		t.FailNow()
+	}
+}
+
+func TestShardedCache_Set(t *testing.T) {
+	sharded, _ := NewShardedCache(4, 16, NewLruCache)
+	if sharded.Set(1, "1") {
+		t.FailNow()
+	}
+	sharded.Add(1, "1")
+	if !sharded.Set(1, "2") {
+		t.FailNow()
+	}
+	if value, _ := sharded.Get(1); value != "2" {
+		t.FailNow()
+	}
+}
+
+func TestShardedCache_Remove(t *testing.T) {
+	sharded, _ := NewShardedCache(4, 16, NewLruCache)
+	sharded.Add(1, "1")
+	if !sharded.Remove(1) || sharded.Remove(1) || sharded.Contains(1) {
+		t.FailNow()
+	}
+}
+
+func TestShardedCache_Clear(t *testing.T) {
+	capacity := 16
+	sharded, _ := NewShardedCache(4, capacity, NewLruCache)
+	for i := 0; i < capacity; i++ {
+		sharded.Add(i, strconv.Itoa(i))
+	}
+	length := sharded.Len()
+	if sharded.Clear() != length || sharded.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestShardedCache_SetCap(t *testing.T) {
+	capacity := 64
+	sharded, _ := NewShardedCache(4, capacity, NewFifoCache)
+	for i := 0; i < capacity; i++ {
+		sharded.Add(i, strconv.Itoa(i))
+	}
+	if sharded.SetCap(3) == nil {
+		t.FailNow()
+	}
+	if sharded.SetCap(capacity*2) != nil || sharded.Cap() != capacity*2 {
+		t.FailNow()
+	}
+	for _, shard := range sharded.shards {
+		if shard.Cap() != capacity/2 {
+			t.FailNow()
+		}
+	}
+}
+
+func TestShardedCache_KeysValues(t *testing.T) {
+	capacity := 64
+	sharded, _ := NewShardedCache(4, capacity, NewLruCache)
+	for i := 0; i < capacity/2; i++ {
+		sharded.Add(i, strconv.Itoa(i))
+	}
+	keys := sharded.Keys()
+	values := sharded.Values()
+	if len(keys) != sharded.Len() || len(values) != sharded.Len() {
+		t.FailNow()
+	}
+	for _, key := range keys {
+		if !contains(values, strconv.Itoa(key.(int))) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestShardedCache_Concurrent(t *testing.T) {
+	capacity := 128
+	sharded, _ := NewShardedCache(8, capacity, NewLruCache)
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := (g*1000 + i) % (capacity * 2)
+				sharded.Add(key, key)
+				if value, ok := sharded.Get(key); ok && value != key {
+					t.Error("unexpected value")
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if sharded.Len() > capacity {
+		t.FailNow()
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	capacity := 1 << 12
+	cache, _ := NewLru[int, int](capacity)
+	benchmarkGet(b, capacity, cache.Add, cache.Get)
+}
+
+func BenchmarkShardedCache_Get(b *testing.B) {
+	capacity := 1 << 12
+	sharded, _ := NewSharded(32, capacity, NewLru[int, int])
+	benchmarkGet(b, capacity, sharded.Add, sharded.Get)
+}
+
+func BenchmarkCache_Add(b *testing.B) {
+	capacity := 1 << 12
+	cache, _ := NewLru[int, int](capacity)
+	benchmarkAdd(b, cache.Add)
+}
+
+func BenchmarkShardedCache_Add(b *testing.B) {
+	capacity := 1 << 12
+	sharded, _ := NewSharded(32, capacity, NewLru[int, int])
+	benchmarkAdd(b, sharded.Add)
+}
+
+func benchmarkGet(b *testing.B, capacity int, add func(int, int) bool, get func(int) (int, bool)) {
+	for i := 0; i < capacity; i++ {
+		add(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			get(i % capacity)
+			i++
+		}
+	})
+}
+
+func benchmarkAdd(b *testing.B, add func(int, int) bool) {
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			add(i, i)
+			i++
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package slru
+
+import (
+	"container/list"
+	"fmt"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+)
+
+// DefaultProtectedRatio is the share of the capacity commonly used for the protected segment.
+const DefaultProtectedRatio = 0.8
+
+// Slru Segmented least recently used cache policy.
+// New entries are added into the probation segment and promoted into the
+// protected segment on their next access, so a single scan can only flush
+// the probation segment.
+type Slru[K comparable, V any] struct {
+	capacity       int
+	protectedRatio float64
+	protectedCap   int
+	elementMap     map[K]*list.Element
+	probation      *list.List
+	protected      *list.List
+	evictHandler   func(key K, value V)
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	owner *list.List
+}
+
+// New returns new typed slru
+func New[K comparable, V any](capacity int, protectedRatio float64) (*Slru[K, V], error) {
+	if capacity <= 0 {
+		return nil, errs.ErrNonPositiveCapacity
+	}
+	if protectedRatio <= 0 || protectedRatio >= 1 {
+		return nil, fmt.Errorf("%w: protected ratio must be between 0 and 1", errs.ErrInvalidArgument)
+	}
+	slru := &Slru[K, V]{
+		capacity:       capacity,
+		protectedRatio: protectedRatio,
+		protectedCap:   int(float64(capacity) * protectedRatio),
+		elementMap:     make(map[K]*list.Element),
+		probation:      list.New(),
+		protected:      list.New(),
+	}
+	return slru, nil
+}
+
+// NewSlru returns new slru
+func NewSlru(capacity int, protectedRatio float64) (*Slru[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity, protectedRatio)
+}
+
+// Add adds entry in cache
+func (s *Slru[K, V]) Add(key K, value V) (eviction bool) {
+	if element, ok := s.elementMap[key]; ok {
+		element.Value.(*entry[K, V]).value = value
+		s.promote(element)
+		return false
+	}
+	eviction = len(s.elementMap) >= s.capacity
+	if eviction {
+		s.evict()
+	}
+	entry := &entry[K, V]{
+		key:   key,
+		value: value,
+		owner: s.probation,
+	}
+	element := s.probation.PushFront(entry)
+	s.elementMap[key] = element
+	return
+}
+
+// Get returns value of cached entry.
+func (s *Slru[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if element, ok := s.elementMap[key]; ok {
+		if trigger {
+			element = s.promote(element)
+		}
+		return element.Value.(*entry[K, V]).value, true
+	}
+	return
+}
+
+// promote moves the entry to the front of the protected segment and
+// demotes protected entries exceeding the segment capacity into probation.
+func (s *Slru[K, V]) promote(element *list.Element) *list.Element {
+	entry := element.Value.(*entry[K, V])
+	if entry.owner == s.protected {
+		s.protected.MoveToFront(element)
+		return element
+	}
+	element = s.move(element, s.protected)
+	for s.protected.Len() > s.protectedCap {
+		s.move(s.protected.Back(), s.probation)
+	}
+	return element
+}
+
+func (s *Slru[K, V]) move(element *list.Element, to *list.List) *list.Element {
+	entry := element.Value.(*entry[K, V])
+	entry.owner.Remove(element)
+	entry.owner = to
+	element = to.PushFront(entry)
+	s.elementMap[entry.key] = element
+	return element
+}
+
+// Remove removes cache entry.
+func (s *Slru[K, V]) Remove(key K) bool {
+	element, ok := s.elementMap[key]
+	if !ok {
+		return false
+	}
+	delete(s.elementMap, key)
+	element.Value.(*entry[K, V]).owner.Remove(element)
+	return true
+}
+
+// OnEvict sets handler called with entries evicted due to capacity.
+func (s *Slru[K, V]) OnEvict(handler func(key K, value V)) {
+	s.evictHandler = handler
+}
+
+func (s *Slru[K, V]) evict() bool {
+	element := s.probation.Back()
+	if element == nil {
+		element = s.protected.Back()
+	}
+	if element == nil {
+		return false
+	}
+	entry := element.Value.(*entry[K, V])
+	s.Remove(entry.key)
+	if s.evictHandler != nil {
+		s.evictHandler(entry.key, entry.value)
+	}
+	return true
+}
+
+// Clear removes all entries in the cache.
+func (s *Slru[K, V]) Clear() int {
+	length := s.Len()
+	for key := range s.elementMap {
+		delete(s.elementMap, key)
+	}
+	s.probation.Init()
+	s.protected.Init()
+	return length
+}
+
+// Len returns length of the cache.
+func (s *Slru[K, V]) Len() int {
+	return s.probation.Len() + s.protected.Len()
+}
+
+// Cap returns capacity of the cache.
+func (s *Slru[K, V]) Cap() int {
+	return s.capacity
+}
+
+// SetCap set capacity of the cache.
+// Returns error unless newCap is negative value.
+func (s *Slru[K, V]) SetCap(newCapacity int) error {
+	if newCapacity <= 0 {
+		return errs.ErrNonPositiveCapacity
+	}
+	s.capacity = newCapacity
+	s.protectedCap = int(float64(newCapacity) * s.protectedRatio)
+	for s.protected.Len() > s.protectedCap {
+		s.move(s.protected.Back(), s.probation)
+	}
+	for s.Len() > newCapacity {
+		s.evict()
+	}
+	return nil
+}
+
+// Keys returns a slice of entry keys in the cache.
+func (s *Slru[K, V]) Keys() []K {
+	keys := make([]K, 0, len(s.elementMap))
+	for k := range s.elementMap {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns a slice of entry values in the cache.
+func (s *Slru[K, V]) Values() []V {
+	values := make([]V, 0, len(s.elementMap))
+	for _, v := range s.elementMap {
+		values = append(values, v.Value.(*entry[K, V]).value)
+	}
+	return values
+}
+
+// ProtectedRatio returns share of the capacity reserved for the protected segment.
+func (s *Slru[K, V]) ProtectedRatio() float64 {
+	return s.protectedRatio
+}
@@ -0,0 +1,331 @@
+package slru
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNewSlru(t *testing.T) {
+	slru, err := NewSlru(1, DefaultProtectedRatio)
+	if slru == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	slru, err = NewSlru(0, DefaultProtectedRatio)
+	if slru != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	slru, err = NewSlru(-1, DefaultProtectedRatio)
+	if slru != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	slru, err = NewSlru(1, 0)
+	if slru != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	slru, err = NewSlru(1, 1)
+	if slru != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNew(t *testing.T) {
+	slru, err := New[string, int](1, DefaultProtectedRatio)
+	if slru == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	slru.Add("1", 1)
+	if value, ok := slru.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	slru, err = New[string, int](0, DefaultProtectedRatio)
+	if slru != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestSlru_Add(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	for i := 0; i < capacity*2; i++ {
+		slru.Add(i, strconv.Itoa(i))
+	}
+}
+
+func TestSlru_Add2(t *testing.T) {
+	capacity := 3
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	// 1 -> []
+	slru.Add(1, nil)
+	// [1]
+	if !contains(slru.Keys(), 1) {
+		t.FailNow()
+	}
+	// 2 -> [1]
+	slru.Add(2, nil)
+	// [1,2]
+	if !containsAll(slru.Keys(), 1, 2) {
+		t.FailNow()
+	}
+	// 3 -> [1,2]
+	slru.Add(3, nil)
+	// [1,2,3]
+	if !containsAll(slru.Keys(), 1, 2, 3) {
+		t.FailNow()
+	}
+	// 4 -> [1,2,3]
+	slru.Add(4, nil)
+	// [2,3,4]
+	if !containsAll(slru.Keys(), 2, 3, 4) {
+		t.FailNow()
+	}
+	// 2 -> [2,3,4]
+	slru.Add(2, nil)
+	// [3,4,2]
+	if !containsAll(slru.Keys(), 2, 3, 4) {
+		t.FailNow()
+	}
+	// 5 -> [3,4,2]
+	slru.Add(5, nil)
+	// [4,2,5]
+	if !containsAll(slru.Keys(), 2, 4, 5) {
+		t.FailNow()
+	}
+}
+
+func TestSlru_Get(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	for i := 0; i < capacity; i++ {
+		slru.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		value, ok := slru.Get(i, true)
+		if !ok || value.(string) != strconv.Itoa(i) {
+			t.FailNow()
+		}
+	}
+	for i := capacity; i < capacity*2; i++ {
+		value, ok := slru.Get(i, true)
+		if ok || value != nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSlru_Remove(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	for i := 0; i < capacity; i++ {
+		slru.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		ok := slru.Remove(i)
+		if !ok {
+			t.FailNow()
+		}
+	}
+	for i := 0; i < capacity; i++ {
+		ok := slru.Remove(i)
+		if ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSlru_Clear(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	for i := 0; i < capacity; i++ {
+		slru.Add(i, strconv.Itoa(i))
+	}
+	slru.Clear()
+	if slru.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestSlru_Cap(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	if slru.Cap() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestSlru_Len(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	if slru.Len() != 0 {
+		t.FailNow()
+	}
+	for i := 0; i < capacity*2; i++ {
+		slru.Add(i, strconv.Itoa(i))
+		if slru.Len() != int(math.Min(float64(i+1), float64(capacity))) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSlru_SetCap(t *testing.T) {
+	capacity := 10
+	newCapacity := 20
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	err := slru.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if slru.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = 5
+	err = slru.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if slru.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = -1
+	err = slru.SetCap(newCapacity)
+	if err == nil {
+		t.FailNow()
+	}
+	if slru.Cap() == newCapacity {
+		t.FailNow()
+	}
+}
+
+func TestSlru_Keys(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	for i := 0; i < capacity*2; i++ {
+		slru.Add(i, strconv.Itoa(i))
+		keys := slru.Keys()
+		if !contains(keys, i) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSlru_Values(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	for i := 0; i < capacity*2; i++ {
+		slru.Add(i, strconv.Itoa(i))
+		values := slru.Values()
+		if !contains(values, strconv.Itoa(i)) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSlru_Scan(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	for i := 0; i < capacity/2; i++ {
+		slru.Add(i, nil)
+		slru.Get(i, true)
+	}
+	for i := capacity; i < capacity*10; i++ {
+		slru.Add(i, nil)
+	}
+	for i := 0; i < capacity/2; i++ {
+		if _, ok := slru.Get(i, false); !ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSlru_SetCap2(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	for i := 0; i < capacity; i++ {
+		slru.Add(i, nil)
+		slru.Get(i, true)
+	}
+	newCapacity := 5
+	slru.SetCap(newCapacity)
+	if slru.Len() != newCapacity || slru.protected.Len() != 4 {
+		t.FailNow()
+	}
+}
+
+func TestSlru_ProtectedRatio(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, 0.5)
+	if slru.ProtectedRatio() != 0.5 {
+		t.FailNow()
+	}
+}
+
+func contains(s []interface{}, e interface{}) bool {
+	for _, c := range s {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(s []interface{}, es ...interface{}) bool {
+	for _, e := range es {
+		if !contains(s, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSlru_OnEvict(t *testing.T) {
+	capacity := 10
+	slru, _ := NewSlru(capacity, DefaultProtectedRatio)
+	evicted := make([]interface{}, 0)
+	slru.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		slru.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	slru.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || slru.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != 0 {
+		t.FailNow()
+	}
+	if _, ok := slru.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	slru.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || slru.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,155 @@
+package nucleus
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+const snapshotVersion = 1
+
+// Codec creates encoders and decoders of cache snapshots.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder writes snapshot to the underlying writer.
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder reads snapshot from the underlying reader.
+type Decoder interface {
+	Decode(v any) error
+}
+
+var (
+	// GobCodec encodes snapshots with encoding/gob, it is the default codec.
+	// Concrete types of interface keys and values other than basic types
+	// must be registered with gob.Register.
+	GobCodec Codec = gobCodec{}
+	// JSONCodec encodes snapshots with encoding/json. Interface keys and
+	// values are decoded as JSON types, such as float64 for numbers.
+	JSONCodec Codec = jsonCodec{}
+)
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder {
+	return gob.NewEncoder(w)
+}
+
+func (gobCodec) NewDecoder(r io.Reader) Decoder {
+	return gob.NewDecoder(r)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func (jsonCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+type snapshot[K comparable, V any] struct {
+	Version   int
+	Expirable bool
+	Entries   []snapshotEntry[K, V]
+}
+
+// snapshotEntry holds remaining time to live of the entry if snapshot is
+// expirable, zero if it never expires.
+type snapshotEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+	TTL   time.Duration
+}
+
+type ttlPolicy[K comparable] interface {
+	TTL(key K) (ttl time.Duration, ok bool)
+}
+
+// SetCodec sets codec of SaveTo and LoadFrom, nil for GobCodec.
+func (c *Cache[K, V]) SetCodec(codec Codec) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.codec = codec
+}
+
+// SaveTo writes snapshot of the entries to w.
+// Entries are saved in eviction order of OrderedPolicy policies, and with
+// remaining time to live of expirable policies.
+func (c *Cache[K, V]) SaveTo(w io.Writer) error {
+	s, codec := c.snapshot()
+	return codec.NewEncoder(w).Encode(s)
+}
+
+// LoadFrom reads snapshot written by SaveTo from r and adds its entries in
+// the cache, so that OrderedPolicy policies evict them in the same order.
+// Remaining time to live of the entries starts over when they are loaded.
+// Costs of the entries are given by the weigher.
+func (c *Cache[K, V]) LoadFrom(r io.Reader) error {
+	var s snapshot[K, V]
+	if err := c.getCodec().NewDecoder(r).Decode(&s); err != nil {
+		return err
+	}
+	if s.Version != snapshotVersion {
+		return errors.New("unsupported snapshot version")
+	}
+	c.lock.Lock()
+	defer c.unlock()
+	policy, expirable := c.policy.(ExpirablePolicy[K, V])
+	for _, entry := range s.Entries {
+		old, exists := c.policy.Get(entry.Key, false)
+		if expirable && s.Expirable {
+			policy.AddWithTTL(entry.Key, entry.Value, entry.TTL)
+		} else {
+			c.policy.Add(entry.Key, entry.Value)
+		}
+		c.recordPut(entry.Key, old, exists)
+	}
+	return nil
+}
+
+// snapshot collects entries under the lock, so that they are encoded
+// without blocking the cache.
+func (c *Cache[K, V]) snapshot() (snapshot[K, V], Codec) {
+	c.readLock()
+	defer c.readUnlock()
+	s := snapshot[K, V]{
+		Version: snapshotVersion,
+		Entries: make([]snapshotEntry[K, V], 0, c.policy.Len()),
+	}
+	ttlPolicy, ok := c.policy.(ttlPolicy[K])
+	s.Expirable = ok && c.expirable()
+	add := func(key K, value V) bool {
+		entry := snapshotEntry[K, V]{Key: key, Value: value}
+		if s.Expirable {
+			if entry.TTL, ok = ttlPolicy.TTL(key); !ok {
+				return true
+			}
+		}
+		s.Entries = append(s.Entries, entry)
+		return true
+	}
+	c.rangeEntries(add)
+	return s, c.codecOrDefault()
+}
+
+func (c *Cache[K, V]) getCodec() Codec {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.codecOrDefault()
+}
+
+func (c *Cache[K, V]) codecOrDefault() Codec {
+	if c.codec == nil {
+		return GobCodec
+	}
+	return c.codec
+}
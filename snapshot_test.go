@@ -0,0 +1,96 @@
+package nucleus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_SaveTo(t *testing.T) {
+	capacity := 3
+	for _, codec := range []Codec{GobCodec, JSONCodec} {
+		cache, _ := NewLru[string, int](capacity)
+		cache.SetCodec(codec)
+		cache.Add("a", 1)
+		cache.Add("b", 2)
+		cache.Add("c", 3)
+		cache.Get("a")
+		buf := bytes.Buffer{}
+		if err := cache.SaveTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		restored, _ := NewLru[string, int](capacity)
+		restored.SetCodec(codec)
+		if err := restored.LoadFrom(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if restored.Len() != capacity || restored.Stats().Adds != uint64(capacity) {
+			t.FailNow()
+		}
+		if value, ok := restored.Get("a"); !ok || value != 1 {
+			t.FailNow()
+		}
+		evicted := make([]string, 0)
+		restored.OnEvict(func(key string, value int, reason EvictReason) {
+			evicted = append(evicted, key)
+		})
+		restored.Add("d", 4)
+		restored.Add("e", 5)
+		if len(evicted) != 2 || evicted[0] != "b" || evicted[1] != "c" {
+			t.Fatal(evicted)
+		}
+	}
+}
+
+func TestCache_SaveTo2(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruWithSweep[int, string](capacity, time.Hour, 0)
+	cache.Add(1, "1")
+	_, _ = cache.AddWithTTL(2, "2", 20*time.Millisecond)
+	_, _ = cache.AddWithTTL(3, "3", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	buf := bytes.Buffer{}
+	if err := cache.SaveTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := NewTlruWithSweep[int, string](capacity, time.Hour, 0)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Len() != 2 || restored.Contains(3) {
+		t.FailNow()
+	}
+	time.Sleep(20 * time.Millisecond)
+	if !restored.Contains(1) || restored.Contains(2) {
+		t.FailNow()
+	}
+}
+
+func TestCache_LoadFrom(t *testing.T) {
+	capacity := 10
+	cache, _ := NewArcCache(capacity)
+	for i := 0; i < capacity; i++ {
+		cache.Add(i, strings.Repeat("a", i))
+	}
+	buf := bytes.Buffer{}
+	if err := cache.SaveTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := NewTlruCacheWithSweep(capacity, time.Hour, 0)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < capacity; i++ {
+		if value, ok := restored.Get(i); !ok || value != strings.Repeat("a", i) {
+			t.FailNow()
+		}
+	}
+	if restored.LoadFrom(strings.NewReader("{}")) == nil {
+		t.FailNow()
+	}
+	restored.SetCodec(JSONCodec)
+	if restored.LoadFrom(strings.NewReader(`{"Version":2}`)) == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,194 @@
+package nucleus
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const evictReasonCount = int(EvictResized) + 1
+
+// Stats is a snapshot of cache statistics.
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Adds          uint64
+	Updates       uint64
+	Evictions     map[EvictReason]uint64
+	Expirations   uint64
+	LoadSuccesses uint64
+	LoadFailures  uint64
+	TotalLoadTime time.Duration
+}
+
+// Requests returns count of Get calls.
+func (s Stats) Requests() uint64 {
+	return s.Hits + s.Misses
+}
+
+// HitRatio returns share of Get calls which found the entry.
+// Returns 1 if there is no request.
+func (s Stats) HitRatio() float64 {
+	if s.Requests() == 0 {
+		return 1
+	}
+	return float64(s.Hits) / float64(s.Requests())
+}
+
+// Loads returns count of loader calls.
+func (s Stats) Loads() uint64 {
+	return s.LoadSuccesses + s.LoadFailures
+}
+
+// AverageLoadTime returns average duration of loader calls.
+func (s Stats) AverageLoadTime() time.Duration {
+	if s.Loads() == 0 {
+		return 0
+	}
+	return s.TotalLoadTime / time.Duration(s.Loads())
+}
+
+// TotalEvictions returns count of entries left the cache by any reason.
+func (s Stats) TotalEvictions() uint64 {
+	total := uint64(0)
+	for _, count := range s.Evictions {
+		total += count
+	}
+	return total
+}
+
+// add returns sum of the snapshots.
+func (s Stats) add(other Stats) Stats {
+	sum := Stats{
+		Hits:          s.Hits + other.Hits,
+		Misses:        s.Misses + other.Misses,
+		Adds:          s.Adds + other.Adds,
+		Updates:       s.Updates + other.Updates,
+		Evictions:     make(map[EvictReason]uint64, evictReasonCount),
+		Expirations:   s.Expirations + other.Expirations,
+		LoadSuccesses: s.LoadSuccesses + other.LoadSuccesses,
+		LoadFailures:  s.LoadFailures + other.LoadFailures,
+		TotalLoadTime: s.TotalLoadTime + other.TotalLoadTime,
+	}
+	for reason, count := range s.Evictions {
+		sum.Evictions[reason] += count
+	}
+	for reason, count := range other.Evictions {
+		sum.Evictions[reason] += count
+	}
+	return sum
+}
+
+// statsCounter counts cache events with atomics, so that it can be updated
+// under the read lock as well. Events are not counted unless it is enabled.
+type statsCounter struct {
+	enabled       bool
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	adds          atomic.Uint64
+	updates       atomic.Uint64
+	evictions     [evictReasonCount]atomic.Uint64
+	loadSuccesses atomic.Uint64
+	loadFailures  atomic.Uint64
+	loadTimeNs    atomic.Int64
+}
+
+func (s *statsCounter) recordGet(ok bool) {
+	if !s.enabled {
+		return
+	}
+	if ok {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+}
+
+func (s *statsCounter) recordPut(exists bool) {
+	if !s.enabled {
+		return
+	}
+	if exists {
+		s.updates.Add(1)
+	} else {
+		s.adds.Add(1)
+	}
+}
+
+func (s *statsCounter) recordEvictions(reason EvictReason, count int) {
+	if !s.enabled {
+		return
+	}
+	s.evictions[reason].Add(uint64(count))
+}
+
+func (s *statsCounter) recordLoad(err error, duration time.Duration) {
+	if !s.enabled {
+		return
+	}
+	if err == nil {
+		s.loadSuccesses.Add(1)
+	} else {
+		s.loadFailures.Add(1)
+	}
+	s.loadTimeNs.Add(int64(duration))
+}
+
+func (s *statsCounter) snapshot() Stats {
+	stats := Stats{
+		Hits:          s.hits.Load(),
+		Misses:        s.misses.Load(),
+		Adds:          s.adds.Load(),
+		Updates:       s.updates.Load(),
+		Evictions:     make(map[EvictReason]uint64, evictReasonCount),
+		Expirations:   s.evictions[EvictExpired].Load(),
+		LoadSuccesses: s.loadSuccesses.Load(),
+		LoadFailures:  s.loadFailures.Load(),
+		TotalLoadTime: time.Duration(s.loadTimeNs.Load()),
+	}
+	for reason := range s.evictions {
+		stats.Evictions[EvictReason(reason)] = s.evictions[reason].Load()
+	}
+	return stats
+}
+
+func (s *statsCounter) reset() {
+	s.hits.Store(0)
+	s.misses.Store(0)
+	s.adds.Store(0)
+	s.updates.Store(0)
+	for reason := range s.evictions {
+		s.evictions[reason].Store(0)
+	}
+	s.loadSuccesses.Store(0)
+	s.loadFailures.Store(0)
+	s.loadTimeNs.Store(0)
+}
+
+// Stats returns snapshot of the cache statistics, which are zero unless the
+// cache is created with WithStats.
+// Counters are read one by one without locking, so the snapshot may be
+// slightly inconsistent under concurrent use.
+func (c *Cache[K, V]) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// ResetStats resets the cache statistics.
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.reset()
+}
+
+// Stats returns sum of the statistics of all shards.
+func (s *ShardedCache[K, V]) Stats() Stats {
+	stats := Stats{}
+	for _, shard := range s.shards {
+		stats = stats.add(shard.Stats())
+	}
+	return stats
+}
+
+// ResetStats resets the statistics of all shards.
+func (s *ShardedCache[K, V]) ResetStats() {
+	for _, shard := range s.shards {
+		shard.ResetStats()
+	}
+}
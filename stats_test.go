@@ -0,0 +1,151 @@
+package nucleus
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_Stats(t *testing.T) {
+	capacity := 2
+	cache, _ := NewLruCache(capacity)
+	stats := cache.Stats()
+	if stats.Requests() != 0 || stats.HitRatio() != 1 || stats.TotalEvictions() != 0 {
+		t.FailNow()
+	}
+	cache.Add(1, "1")
+	cache.Add(2, "2")
+	cache.Add(2, "2")
+	cache.Set(1, "1")
+	cache.Add(3, "3")
+	cache.Get(3)
+	cache.Get(2)
+	cache.Get(1)
+	cache.Contains(2)
+	cache.Remove(2)
+	_ = cache.SetCap(1)
+	cache.Add(4, "4")
+	_ = cache.SetCap(capacity)
+	cache.Clear()
+	stats = cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Requests() != 3 {
+		t.FailNow()
+	}
+	if stats.HitRatio() != 2.0/3.0 {
+		t.FailNow()
+	}
+	if stats.Adds != 4 || stats.Updates != 2 {
+		t.FailNow()
+	}
+	expected := map[EvictReason]uint64{
+		EvictCapacity: 2,
+		EvictReplaced: 2,
+		EvictRemoved:  0,
+		EvictResized:  1,
+		EvictCleared:  1,
+	}
+	for reason, count := range expected {
+		if stats.Evictions[reason] != count {
+			t.Fatal(reason)
+		}
+	}
+	if stats.TotalEvictions() != 6 {
+		t.FailNow()
+	}
+	cache.ResetStats()
+	stats = cache.Stats()
+	if stats.Requests() != 0 || stats.Adds != 0 || stats.TotalEvictions() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCache_Stats2(t *testing.T) {
+	capacity := 10
+	cache, _ := NewTlruCacheWithSweep(capacity, 10*time.Millisecond, time.Hour)
+	defer cache.Close()
+	cache.Add(1, "1")
+	cache.Add(2, "2")
+	time.Sleep(20 * time.Millisecond)
+	cache.Get(1)
+	cache.Len()
+	stats := cache.Stats()
+	if stats.Expirations != 2 || stats.Evictions[EvictExpired] != 2 || stats.Misses != 1 {
+		t.FailNow()
+	}
+}
+
+func TestCache_Stats3(t *testing.T) {
+	capacity := 10
+	cache, _ := NewLruCache(capacity)
+	loadErr := errors.New("load failed")
+	loader := func(ctx context.Context, key interface{}) (interface{}, error) {
+		time.Sleep(time.Millisecond)
+		if key == 0 {
+			return nil, loadErr
+		}
+		return key, nil
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = cache.GetOrLoad(context.Background(), i, loader)
+	}
+	_, _ = cache.GetOrLoad(context.Background(), 1, loader)
+	stats := cache.Stats()
+	if stats.LoadSuccesses != 2 || stats.LoadFailures != 1 || stats.Loads() != 3 {
+		t.FailNow()
+	}
+	if stats.TotalLoadTime < 3*time.Millisecond || stats.AverageLoadTime() < time.Millisecond {
+		t.FailNow()
+	}
+	if stats.Hits != 1 || stats.Misses != 3 {
+		t.FailNow()
+	}
+	if (Stats{}).AverageLoadTime() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCache_Stats4(t *testing.T) {
+	capacity := 100
+	cache, _ := NewClockCache(capacity)
+	for i := 0; i < capacity; i++ {
+		cache.Add(i, strconv.Itoa(i))
+	}
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < capacity*2; i++ {
+				cache.Get(i)
+			}
+		}()
+	}
+	wg.Wait()
+	stats := cache.Stats()
+	if stats.Hits != uint64(8*capacity) || stats.Misses != uint64(8*capacity) {
+		t.FailNow()
+	}
+}
+
+func TestShardedCache_Stats(t *testing.T) {
+	capacity := 8
+	sharded, _ := NewShardedCache(4, capacity, NewLruCache)
+	for i := 0; i < capacity*2; i++ {
+		sharded.Add(i, i)
+		sharded.Get(i)
+	}
+	stats := sharded.Stats()
+	if stats.Adds != uint64(capacity*2) || stats.Hits != uint64(capacity*2) {
+		t.FailNow()
+	}
+	if stats.Evictions[EvictCapacity] != uint64(capacity*2-sharded.Len()) {
+		t.FailNow()
+	}
+	sharded.ResetStats()
+	if sharded.Stats().Adds != 0 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,142 @@
+package tinylfu
+
+import (
+	"github.com/SemihBKGR/nucleus/internal/keyhash"
+	"math/bits"
+)
+
+const (
+	sketchDepth    = 4
+	sketchMaxCount = 15
+)
+
+// sketchSeeds are mixed into the hash of a key separately for every row.
+var sketchSeeds = [sketchDepth]uint64{
+	0xc3a5c85c97cb3127, 0xb492b66fbe98f273, 0x9ae16a3b2f90404f, 0xcbf29ce484222325,
+}
+
+// sketch is a count-min sketch with a doorkeeper bloom filter in front of it.
+// The first occurrence of a key only sets the doorkeeper, so one-hit wonders
+// never reach the counters. Once additions reach sampleSize all counters are
+// halved and the doorkeeper is cleared so that old popularity fades away.
+type sketch[K comparable] struct {
+	hasher     keyhash.Hasher[K]
+	counters   [sketchDepth][]uint8
+	mask       uint64
+	doorkeeper []uint64
+	additions  int
+	sampleSize int
+}
+
+func newSketch[K comparable](capacity int) *sketch[K] {
+	width := nextPowerOfTwo(max(4*capacity, 64))
+	s := &sketch[K]{
+		hasher:     keyhash.New[K](),
+		mask:       uint64(width - 1),
+		doorkeeper: make([]uint64, nextPowerOfTwo(10*capacity)/8+1),
+		sampleSize: 10 * capacity,
+	}
+	for i := range s.counters {
+		s.counters[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// Increment records an access of the key.
+func (s *sketch[K]) Increment(key K) {
+	s.additions++
+	if s.additions >= s.sampleSize {
+		s.reset()
+	}
+	h := s.hash(key)
+	if !s.admitDoorkeeper(h) {
+		return
+	}
+	for i := range s.counters {
+		index := s.index(h, i)
+		if s.counters[i][index] < sketchMaxCount {
+			s.counters[i][index]++
+		}
+	}
+}
+
+// Estimate returns estimated access frequency of the key.
+func (s *sketch[K]) Estimate(key K) int {
+	h := s.hash(key)
+	estimate := uint8(sketchMaxCount)
+	for i := range s.counters {
+		index := s.index(h, i)
+		estimate = min(estimate, s.counters[i][index])
+	}
+	if s.containsDoorkeeper(h) {
+		return int(estimate) + 1
+	}
+	return int(estimate)
+}
+
+// Clear resets all recorded frequencies.
+func (s *sketch[K]) Clear() {
+	for i := range s.counters {
+		clear(s.counters[i])
+	}
+	clear(s.doorkeeper)
+	s.additions = 0
+}
+
+func (s *sketch[K]) reset() {
+	for i := range s.counters {
+		for j := range s.counters[i] {
+			s.counters[i][j] >>= 1
+		}
+	}
+	clear(s.doorkeeper)
+	s.additions /= 2
+}
+
+// admitDoorkeeper sets the doorkeeper bits of the key.
+// Returns true if the key had already passed the doorkeeper.
+func (s *sketch[K]) admitDoorkeeper(h uint64) bool {
+	if s.containsDoorkeeper(h) {
+		return true
+	}
+	size := uint64(len(s.doorkeeper) * 64)
+	for i := 0; i < sketchDepth; i++ {
+		bit := mix(h, i) % size
+		s.doorkeeper[bit/64] |= 1 << (bit % 64)
+	}
+	return false
+}
+
+func (s *sketch[K]) containsDoorkeeper(h uint64) bool {
+	size := uint64(len(s.doorkeeper) * 64)
+	for i := 0; i < sketchDepth; i++ {
+		bit := mix(h, i) % size
+		if s.doorkeeper[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// index returns the counter of the key in the row.
+func (s *sketch[K]) index(h uint64, row int) uint64 {
+	return mix(h, row) & s.mask
+}
+
+// mix mixes the hash of a key with the seed of the row, so keys sharing a
+// counter or a doorkeeper bit in one row rarely share them in the others.
+func mix(h uint64, row int) uint64 {
+	h = (h + sketchSeeds[row]) * sketchSeeds[row]
+	return h ^ h>>32
+}
+
+func (s *sketch[K]) hash(key K) uint64 {
+	return s.hasher.Sum(key)
+}
+
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len(uint(n-1))
+}
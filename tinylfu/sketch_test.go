@@ -0,0 +1,53 @@
+package tinylfu
+
+import "testing"
+
+func TestSketch_Increment(t *testing.T) {
+	sketch := newSketch[int](100)
+	if sketch.Estimate(1) != 0 {
+		t.FailNow()
+	}
+	sketch.Increment(1)
+	if sketch.Estimate(1) != 1 {
+		t.FailNow()
+	}
+	for i := 0; i < 5; i++ {
+		sketch.Increment(1)
+	}
+	if sketch.Estimate(1) != 6 {
+		t.FailNow()
+	}
+	for i := 0; i < sketchMaxCount*2; i++ {
+		sketch.Increment(2)
+	}
+	if sketch.Estimate(2) != sketchMaxCount+1 {
+		t.FailNow()
+	}
+}
+
+func TestSketch_Reset(t *testing.T) {
+	capacity := 10
+	sketch := newSketch[int](capacity)
+	for i := 0; i < 9; i++ {
+		sketch.Increment(1)
+	}
+	if sketch.Estimate(1) != 9 {
+		t.FailNow()
+	}
+	for i := 0; i < sketch.sampleSize; i++ {
+		sketch.Increment(i + 1000)
+	}
+	if sketch.Estimate(1) >= 9 {
+		t.FailNow()
+	}
+}
+
+func TestSketch_Clear(t *testing.T) {
+	sketch := newSketch[int](10)
+	sketch.Increment(1)
+	sketch.Increment(1)
+	sketch.Clear()
+	if sketch.Estimate(1) != 0 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,252 @@
+package tinylfu
+
+import (
+	"container/list"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+)
+
+const (
+	windowPercentage    = 1
+	protectedPercentage = 80
+)
+
+// TinyLfu Window tiny least frequently used cache policy.
+// New entries are admitted into a small lru window. Entries leaving the
+// window compete with the eviction candidate of the segmented main lru and
+// only the one with the higher estimated frequency is kept.
+type TinyLfu[K comparable, V any] struct {
+	capacity     int
+	windowCap    int
+	protectedCap int
+	elementMap   map[K]*list.Element
+	window       *list.List
+	probation    *list.List
+	protected    *list.List
+	sketch       *sketch[K]
+	evictHandler func(key K, value V)
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	owner *list.List
+}
+
+// New returns new typed tinylfu
+func New[K comparable, V any](capacity int) (*TinyLfu[K, V], error) {
+	if capacity <= 0 {
+		return nil, errs.ErrNonPositiveCapacity
+	}
+	tinyLfu := &TinyLfu[K, V]{
+		elementMap: make(map[K]*list.Element),
+		window:     list.New(),
+		probation:  list.New(),
+		protected:  list.New(),
+	}
+	tinyLfu.resize(capacity)
+	return tinyLfu, nil
+}
+
+// NewTinyLfu returns new tinylfu
+func NewTinyLfu(capacity int) (*TinyLfu[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity)
+}
+
+// Add adds entry in cache
+func (t *TinyLfu[K, V]) Add(key K, value V) (eviction bool) {
+	t.sketch.Increment(key)
+	if element, ok := t.elementMap[key]; ok {
+		element.Value.(*entry[K, V]).value = value
+		t.touch(element)
+		return false
+	}
+	entry := &entry[K, V]{
+		key:   key,
+		value: value,
+		owner: t.window,
+	}
+	t.elementMap[key] = t.window.PushFront(entry)
+	if t.window.Len() > t.windowCap {
+		eviction = t.admit(t.window.Back())
+	}
+	return
+}
+
+// admit moves the candidate leaving the window into the main segments.
+// When the main segments are full the candidate and the main victim compete
+// and the less frequently used one is evicted.
+// Returns true if an entry is evicted.
+func (t *TinyLfu[K, V]) admit(candidate *list.Element) bool {
+	if t.probation.Len()+t.protected.Len() < t.capacity-t.windowCap {
+		t.move(candidate, t.probation)
+		return false
+	}
+	victim := t.probation.Back()
+	if victim == nil {
+		victim = t.protected.Back()
+	}
+	if victim == nil {
+		t.evictElement(candidate)
+		return true
+	}
+	candidateKey := candidate.Value.(*entry[K, V]).key
+	victimKey := victim.Value.(*entry[K, V]).key
+	if t.sketch.Estimate(candidateKey) > t.sketch.Estimate(victimKey) {
+		t.evictElement(victim)
+		t.move(candidate, t.probation)
+	} else {
+		t.evictElement(candidate)
+	}
+	return true
+}
+
+// Get returns value of cached entry.
+func (t *TinyLfu[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if trigger {
+		t.sketch.Increment(key)
+	}
+	if element, ok := t.elementMap[key]; ok {
+		if trigger {
+			t.touch(element)
+		}
+		return element.Value.(*entry[K, V]).value, true
+	}
+	return
+}
+
+// touch updates recency of the entry, promoting probation entries into
+// the protected segment.
+func (t *TinyLfu[K, V]) touch(element *list.Element) {
+	switch element.Value.(*entry[K, V]).owner {
+	case t.window:
+		t.window.MoveToFront(element)
+	case t.probation:
+		t.move(element, t.protected)
+		for t.protected.Len() > t.protectedCap {
+			t.move(t.protected.Back(), t.probation)
+		}
+	case t.protected:
+		t.protected.MoveToFront(element)
+	}
+}
+
+func (t *TinyLfu[K, V]) move(element *list.Element, to *list.List) {
+	entry := element.Value.(*entry[K, V])
+	entry.owner.Remove(element)
+	entry.owner = to
+	t.elementMap[entry.key] = to.PushFront(entry)
+}
+
+func (t *TinyLfu[K, V]) remove(element *list.Element) {
+	entry := element.Value.(*entry[K, V])
+	delete(t.elementMap, entry.key)
+	entry.owner.Remove(element)
+}
+
+// OnEvict sets handler called with entries evicted due to capacity,
+// including candidates rejected by the admission policy.
+func (t *TinyLfu[K, V]) OnEvict(handler func(key K, value V)) {
+	t.evictHandler = handler
+}
+
+func (t *TinyLfu[K, V]) evictElement(element *list.Element) {
+	t.remove(element)
+	if t.evictHandler != nil {
+		entry := element.Value.(*entry[K, V])
+		t.evictHandler(entry.key, entry.value)
+	}
+}
+
+// Remove removes cache entry.
+func (t *TinyLfu[K, V]) Remove(key K) bool {
+	element, ok := t.elementMap[key]
+	if !ok {
+		return false
+	}
+	t.remove(element)
+	return true
+}
+
+func (t *TinyLfu[K, V]) evict() bool {
+	for _, segment := range []*list.List{t.probation, t.protected, t.window} {
+		if element := segment.Back(); element != nil {
+			t.evictElement(element)
+			return true
+		}
+	}
+	return false
+}
+
+// resize recalculates segment capacities and evicts entries that no longer fit.
+// The sketch is rebuilt in the size of the new capacity, since its counters
+// and doorkeeper are sized by the capacity.
+func (t *TinyLfu[K, V]) resize(capacity int) {
+	if capacity != t.capacity {
+		t.sketch = newSketch[K](capacity)
+	}
+	t.capacity = capacity
+	t.windowCap = max(1, capacity*windowPercentage/100)
+	t.protectedCap = (capacity - t.windowCap) * protectedPercentage / 100
+	for t.window.Len() > t.windowCap {
+		t.move(t.window.Back(), t.probation)
+	}
+	for t.protected.Len() > t.protectedCap {
+		t.move(t.protected.Back(), t.probation)
+	}
+	// Add admits into the main segments only while they have room, so they
+	// must fit the capacity left to them besides the window.
+	for t.probation.Len()+t.protected.Len() > capacity-t.windowCap {
+		t.evict()
+	}
+}
+
+// Clear removes all entries in the cache.
+func (t *TinyLfu[K, V]) Clear() int {
+	length := t.Len()
+	for key := range t.elementMap {
+		delete(t.elementMap, key)
+	}
+	t.window.Init()
+	t.probation.Init()
+	t.protected.Init()
+	t.sketch.Clear()
+	return length
+}
+
+// Len returns length of the cache.
+func (t *TinyLfu[K, V]) Len() int {
+	return len(t.elementMap)
+}
+
+// Cap returns capacity of the cache.
+func (t *TinyLfu[K, V]) Cap() int {
+	return t.capacity
+}
+
+// SetCap set capacity of the cache.
+// Returns error unless newCap is negative value.
+func (t *TinyLfu[K, V]) SetCap(newCapacity int) error {
+	if newCapacity <= 0 {
+		return errs.ErrNonPositiveCapacity
+	}
+	t.resize(newCapacity)
+	return nil
+}
+
+// Keys returns a slice of entry keys in the cache.
+func (t *TinyLfu[K, V]) Keys() []K {
+	keys := make([]K, 0, len(t.elementMap))
+	for k := range t.elementMap {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns a slice of entry values in the cache.
+func (t *TinyLfu[K, V]) Values() []V {
+	values := make([]V, 0, len(t.elementMap))
+	for _, v := range t.elementMap {
+		values = append(values, v.Value.(*entry[K, V]).value)
+	}
+	return values
+}
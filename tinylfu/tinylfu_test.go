@@ -0,0 +1,356 @@
+package tinylfu
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNewTinyLfu(t *testing.T) {
+	tinyLfu, err := NewTinyLfu(1)
+	if tinyLfu == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	tinyLfu, err = NewTinyLfu(0)
+	if tinyLfu != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	tinyLfu, err = NewTinyLfu(-1)
+	if tinyLfu != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNew(t *testing.T) {
+	tinyLfu, err := New[string, int](1)
+	if tinyLfu == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	tinyLfu.Add("1", 1)
+	if value, ok := tinyLfu.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	tinyLfu, err = New[string, int](0)
+	if tinyLfu != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestTinyLfu_Add(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := NewTinyLfu(capacity)
+	for i := 0; i < capacity*2; i++ {
+		tinyLfu.Add(i, strconv.Itoa(i))
+	}
+}
+
+func TestTinyLfu_Add2(t *testing.T) {
+	capacity := 3
+	tinyLfu, _ := NewTinyLfu(capacity)
+	// 1 -> w:[] p:[]
+	tinyLfu.Add(1, nil)
+	// w:[1] p:[]
+	if !contains(tinyLfu.Keys(), 1) {
+		t.FailNow()
+	}
+	// 2 -> w:[1] p:[]
+	tinyLfu.Add(2, nil)
+	// w:[2] p:[1]
+	if !containsAll(tinyLfu.Keys(), 1, 2) {
+		t.FailNow()
+	}
+	// 3 -> w:[2] p:[1]
+	tinyLfu.Add(3, nil)
+	// w:[3] p:[2,1]
+	if !containsAll(tinyLfu.Keys(), 1, 2, 3) {
+		t.FailNow()
+	}
+	// 4 -> w:[3] p:[2,1]
+	tinyLfu.Add(4, nil)
+	// 3 is not more frequent than 1 so it is rejected
+	// w:[4] p:[2,1]
+	if !containsAll(tinyLfu.Keys(), 1, 2, 4) {
+		t.FailNow()
+	}
+	// 5 -> w:[4] p:[2,1]
+	tinyLfu.Add(4, nil)
+	tinyLfu.Add(4, nil)
+	tinyLfu.Add(5, nil)
+	// 4 is more frequent than 1 so 1 is evicted
+	// w:[5] p:[4,2]
+	if !containsAll(tinyLfu.Keys(), 2, 4, 5) {
+		t.FailNow()
+	}
+}
+
+func TestTinyLfu_Get(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := NewTinyLfu(capacity)
+	for i := 0; i < capacity; i++ {
+		tinyLfu.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		value, ok := tinyLfu.Get(i, true)
+		if !ok || value.(string) != strconv.Itoa(i) {
+			t.FailNow()
+		}
+	}
+	for i := capacity; i < capacity*2; i++ {
+		value, ok := tinyLfu.Get(i, true)
+		if ok || value != nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTinyLfu_Remove(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := NewTinyLfu(capacity)
+	for i := 0; i < capacity; i++ {
+		tinyLfu.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		ok := tinyLfu.Remove(i)
+		if !ok {
+			t.FailNow()
+		}
+	}
+	for i := 0; i < capacity; i++ {
+		ok := tinyLfu.Remove(i)
+		if ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTinyLfu_Clear(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := NewTinyLfu(capacity)
+	for i := 0; i < capacity; i++ {
+		tinyLfu.Add(i, strconv.Itoa(i))
+	}
+	tinyLfu.Clear()
+	if tinyLfu.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestTinyLfu_Cap(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := NewTinyLfu(capacity)
+	if tinyLfu.Cap() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestTinyLfu_Len(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := NewTinyLfu(capacity)
+	if tinyLfu.Len() != 0 {
+		t.FailNow()
+	}
+	for i := 0; i < capacity*2; i++ {
+		tinyLfu.Add(i, strconv.Itoa(i))
+		if tinyLfu.Len() != int(math.Min(float64(i+1), float64(capacity))) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTinyLfu_SetCap(t *testing.T) {
+	capacity := 10
+	newCapacity := 20
+	tinyLfu, _ := NewTinyLfu(capacity)
+	err := tinyLfu.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if tinyLfu.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = 5
+	err = tinyLfu.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if tinyLfu.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = -1
+	err = tinyLfu.SetCap(newCapacity)
+	if err == nil {
+		t.FailNow()
+	}
+	if tinyLfu.Cap() == newCapacity {
+		t.FailNow()
+	}
+}
+
+func TestTinyLfu_Keys(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := NewTinyLfu(capacity)
+	for i := 0; i < capacity*2; i++ {
+		tinyLfu.Add(i, strconv.Itoa(i))
+		keys := tinyLfu.Keys()
+		if !contains(keys, i) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTinyLfu_Values(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := NewTinyLfu(capacity)
+	for i := 0; i < capacity*2; i++ {
+		tinyLfu.Add(i, strconv.Itoa(i))
+		values := tinyLfu.Values()
+		if !contains(values, strconv.Itoa(i)) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTinyLfu_Scan(t *testing.T) {
+	capacity := 100
+	tinyLfu, _ := NewTinyLfu(capacity)
+	for j := 0; j < 5; j++ {
+		for i := 0; i < capacity/2; i++ {
+			if _, ok := tinyLfu.Get(i, true); !ok {
+				tinyLfu.Add(i, nil)
+			}
+		}
+	}
+	for i := capacity; i < capacity*5; i++ {
+		tinyLfu.Add(i, nil)
+	}
+	for i := 0; i < capacity/2; i++ {
+		if _, ok := tinyLfu.Get(i, false); !ok {
+			t.FailNow()
+		}
+	}
+	if tinyLfu.Len() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestTinyLfu_SetCap2(t *testing.T) {
+	capacity := 100
+	tinyLfu, _ := NewTinyLfu(capacity)
+	for i := 0; i < capacity; i++ {
+		tinyLfu.Add(i, nil)
+		tinyLfu.Get(i, true)
+	}
+	newCapacity := 10
+	tinyLfu.SetCap(newCapacity)
+	if tinyLfu.Len() != newCapacity {
+		t.FailNow()
+	}
+	if tinyLfu.window.Len() > tinyLfu.windowCap || tinyLfu.protected.Len() > tinyLfu.protectedCap {
+		t.FailNow()
+	}
+}
+
+func TestTinyLfu_SetCap3(t *testing.T) {
+	capacity := 10
+	tinyLfu, _ := New[int, int](capacity)
+	for i := 0; i < capacity; i++ {
+		tinyLfu.Add(i, i)
+	}
+	// the window is left empty while the main segments are full
+	tinyLfu.Remove(capacity - 1)
+	newCapacity := 5
+	tinyLfu.SetCap(newCapacity)
+	tinyLfu.Add(100, 100)
+	tinyLfu.Add(101, 101)
+	if tinyLfu.Len() != newCapacity || tinyLfu.Cap() != newCapacity {
+		t.FailNow()
+	}
+}
+
+func TestTinyLfu_SetCap4(t *testing.T) {
+	tinyLfu, _ := New[int, int](1)
+	capacity := 100000
+	tinyLfu.SetCap(capacity)
+	if tinyLfu.sketch.sampleSize != 10*capacity || len(tinyLfu.sketch.doorkeeper)*64 < 10*capacity {
+		t.FailNow()
+	}
+	// frequent keys are admitted over one-hit wonders
+	for i := 0; i < capacity; i++ {
+		tinyLfu.Add(i, i)
+		tinyLfu.Get(i, true)
+	}
+	for i := capacity; i < capacity*2; i++ {
+		tinyLfu.Add(i, i)
+	}
+	hits := 0
+	for i := 0; i < capacity; i++ {
+		if _, ok := tinyLfu.Get(i, false); ok {
+			hits++
+		}
+	}
+	if hits < capacity*9/10 {
+		t.FailNow()
+	}
+}
+
+func contains(s []interface{}, e interface{}) bool {
+	for _, c := range s {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(s []interface{}, es ...interface{}) bool {
+	for _, e := range es {
+		if !contains(s, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTinyLfu_OnEvict(t *testing.T) {
+	capacity := 10
+	tinylfu, _ := NewTinyLfu(capacity)
+	evicted := make([]interface{}, 0)
+	tinylfu.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		tinylfu.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	tinylfu.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || tinylfu.Len() != capacity {
+		t.FailNow()
+	}
+	if _, ok := tinylfu.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	tinylfu.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || tinylfu.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
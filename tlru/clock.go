@@ -0,0 +1,30 @@
+package tlru
+
+import "time"
+
+// Clock provides current time and timers to the tlru, so that expiration
+// can be driven by a fake clock in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SetClock sets clock of the tlru, nil for the system clock.
+// It should be set before entries are added and the daemon is started,
+// since times of the entries are taken from the clock.
+func (t *Tlru[K, V]) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	t.clock = clock
+}
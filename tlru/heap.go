@@ -0,0 +1,45 @@
+package tlru
+
+import "github.com/SemihBKGR/nucleus/policyutil"
+
+// expirationHeap is a min-heap of entries ordered by expiration time.
+// It implements heap.Interface and keeps entry indexes up to date, so that
+// updated or removed entries can be fixed in O(log n).
+type expirationHeap[K comparable, V any] []*policyutil.Entry[K, item[V]]
+
+func (h expirationHeap[K, V]) Len() int {
+	return len(h)
+}
+
+func (h expirationHeap[K, V]) Less(i, j int) bool {
+	return h[i].Value.expirationNs < h[j].Value.expirationNs
+}
+
+func (h expirationHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].Value.index = i
+	h[j].Value.index = j
+}
+
+func (h *expirationHeap[K, V]) Push(x any) {
+	entry := x.(*policyutil.Entry[K, item[V]])
+	entry.Value.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expirationHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.Value.index = -1
+	*h = old[:n-1]
+	return entry
+}
+
+func (h expirationHeap[K, V]) peek() *policyutil.Entry[K, item[V]] {
+	if len(h) == 0 {
+		return nil
+	}
+	return h[0]
+}
@@ -1,187 +1,491 @@
 package tlru
 
 import (
-	"container/list"
-	"errors"
+	"container/heap"
+	"context"
+	"fmt"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+	"github.com/SemihBKGR/nucleus/policyutil"
 	"sync"
 	"time"
 )
 
 // Tlru Time Aware Least recently used cache policy
-type Tlru struct {
+type Tlru[K comparable, V any] struct {
 	capacity           int
-	elementMap         map[interface{}]*list.Element
-	evictionList       *list.List
+	evictionList       *policyutil.List[K, item[V]]
+	expirationHeap     expirationHeap[K, V]
 	expirationDuration time.Duration
+	sweepInterval      time.Duration
 	daemonStarted      bool
+	evictHandler       func(key K, value V)
+	expireHandler      func(key K, value V)
+	weigher            func(key K, value V) int64
+	maxCost            int64
+	daemonLock         sync.Mutex
+	daemonCancel       context.CancelFunc
+	daemonDone         chan struct{}
+	clock              Clock
 }
 
-type entry struct {
-	key    interface{}
-	value  interface{}
-	timeMs int64
+// item is value of the eviction list entries, holding the cached value
+// with its times and index in the expiration heap.
+type item[V any] struct {
+	value        V
+	timeNs       int64
+	expirationNs int64
+	index        int
 }
 
-// NewTlru returns new tlru
-func NewTlru(capacity int, expiration time.Duration) (*Tlru, error) {
+// New returns new typed tlru
+func New[K comparable, V any](capacity int, expiration time.Duration) (*Tlru[K, V], error) {
 	if capacity <= 0 {
-		return nil, errors.New("capacity must be positive value")
+		return nil, errs.ErrNonPositiveCapacity
 	}
-	tlru := &Tlru{
+	tlru := &Tlru[K, V]{
 		capacity:           capacity,
-		elementMap:         make(map[interface{}]*list.Element),
-		evictionList:       list.New(),
+		evictionList:       policyutil.NewList[K, item[V]](),
 		expirationDuration: expiration,
 		daemonStarted:      false,
+		clock:              systemClock{},
 	}
 	return tlru, nil
 }
 
+// NewTlru returns new tlru
+func NewTlru(capacity int, expiration time.Duration) (*Tlru[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity, expiration)
+}
+
 // StartDaemon starts time expiration daemon
-func (t *Tlru) StartDaemon(lock *sync.RWMutex) (ok bool) {
+func (t *Tlru[K, V]) StartDaemon(lock *sync.RWMutex) (ok bool) {
+	return t.StartDaemonContext(context.Background(), lock)
+}
+
+// StartDaemonContext starts time expiration daemon which runs until ctx is
+// done or StopDaemon is called, after which it can be started again.
+// Expired entries are removed every sweep interval while holding the lock.
+func (t *Tlru[K, V]) StartDaemonContext(ctx context.Context, lock sync.Locker) (ok bool) {
+	t.daemonLock.Lock()
+	defer t.daemonLock.Unlock()
 	if t.daemonStarted {
 		return false
 	}
-	if t.expirationDuration <= 0 {
+	interval := t.SweepInterval()
+	if interval <= 0 {
 		return false
 	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
 	t.daemonStarted = true
+	t.daemonCancel = cancel
+	t.daemonDone = done
+	clock := t.clock
 	go func() {
+		defer close(done)
 		for {
-			time.Sleep(t.expirationDuration)
-			expiredKeys := make([]interface{}, 0)
-			lock.RLock()
-			currentTimeMs := time.Now().UnixMilli()
-			element := t.evictionList.Back()
-			for element != nil {
-				entry := element.Value.(*entry)
-				if entry.timeMs+t.expirationDuration.Milliseconds() <= currentTimeMs {
-					expiredKeys = append(expiredKeys, entry.key)
-				}
-				element = element.Prev()
-			}
-			lock.RUnlock()
-			lock.Lock()
-			for _, k := range expiredKeys {
-				t.Remove(k)
+			select {
+			case <-ctx.Done():
+				t.daemonExited(done)
+				return
+			case <-clock.After(interval):
+				lock.Lock()
+				t.Expire()
+				lock.Unlock()
 			}
-			lock.Unlock()
 		}
 	}()
 	return true
 }
 
-// Add adds entry in cache
-func (t *Tlru) Add(key, value interface{}) (eviction bool) {
-	if element, ok := t.elementMap[key]; ok {
-		t.evictionList.MoveToFront(element)
-		element.Value.(*entry).value = value
+// daemonExited resets the daemon state when the daemon of done exits since
+// its ctx is done, unless it is already stopped by StopDaemon.
+func (t *Tlru[K, V]) daemonExited(done chan struct{}) {
+	t.daemonLock.Lock()
+	defer t.daemonLock.Unlock()
+	if t.daemonDone != done {
+		return
+	}
+	t.daemonCancel()
+	t.daemonStarted = false
+	t.daemonCancel = nil
+	t.daemonDone = nil
+}
+
+// StopDaemon stops time expiration daemon and waits until it exits.
+// It must not be called while holding the lock given to the daemon.
+// Returns false if daemon is not started or already exited as its ctx is
+// done.
+func (t *Tlru[K, V]) StopDaemon() bool {
+	t.daemonLock.Lock()
+	if !t.daemonStarted {
+		t.daemonLock.Unlock()
 		return false
 	}
-	eviction = len(t.elementMap) >= t.capacity
-	if eviction {
-		t.evict()
+	cancel, done := t.daemonCancel, t.daemonDone
+	t.daemonStarted = false
+	t.daemonCancel = nil
+	t.daemonDone = nil
+	t.daemonLock.Unlock()
+	cancel()
+	<-done
+	return true
+}
+
+// Add adds entry in cache
+// Entry expires after the default expiration duration, an existing entry
+// keeps its ttl which restarts.
+// Cost of the entry is given by the weigher, or 1 if there is none.
+func (t *Tlru[K, V]) Add(key K, value V) (eviction bool) {
+	return t.add(key, value, t.ttlOf(key), t.weigh(key, value))
+}
+
+// AddWithTTL adds entry in cache which expires after given ttl.
+// Entry never expires unless ttl is positive value.
+func (t *Tlru[K, V]) AddWithTTL(key K, value V, ttl time.Duration) (eviction bool) {
+	return t.add(key, value, ttl, t.weigh(key, value))
+}
+
+// AddWithCost adds entry in cache with given cost.
+// Entry expires after the default expiration duration, an existing entry
+// keeps its ttl which restarts.
+func (t *Tlru[K, V]) AddWithCost(key K, value V, cost int64) (eviction bool) {
+	return t.add(key, value, t.ttlOf(key), cost)
+}
+
+// ttlOf returns ttl the entry of the key is added with, or the default
+// expiration duration if there is no such entry.
+func (t *Tlru[K, V]) ttlOf(key K) time.Duration {
+	entry, ok := t.evictionList.Get(key)
+	if !ok || entry.Value.expired(t.clock.Now().UnixNano()) {
+		return t.expirationDuration
 	}
-	entry := &entry{
-		key:    key,
-		value:  value,
-		timeMs: time.Now().UnixMilli(),
+	if entry.Value.expirationNs == 0 {
+		return 0
+	}
+	return time.Duration(entry.Value.expirationNs - entry.Value.timeNs)
+}
+
+// add adds entry in cache. Entries are evicted until both length and total
+// cost fit, an entry whose cost exceeds max cost is evicted right away.
+func (t *Tlru[K, V]) add(key K, value V, ttl time.Duration, cost int64) (eviction bool) {
+	cost = max(cost, 0)
+	t.Remove(key)
+	if t.maxCost > 0 && cost > t.maxCost {
+		if t.evictHandler != nil {
+			t.evictHandler(key, value)
+		}
+		return true
 	}
-	element := t.evictionList.PushFront(entry)
-	t.elementMap[key] = element
+	if t.full(cost) {
+		t.Expire()
+	}
+	for t.full(cost) && t.evict() {
+		eviction = true
+	}
+	currentTimeNs := t.clock.Now().UnixNano()
+	expirationNs := int64(0)
+	if ttl > 0 {
+		expirationNs = currentTimeNs + int64(ttl)
+	}
+	entry := t.evictionList.PushFront(key, item[V]{
+		value:  value,
+		timeNs: currentTimeNs,
+		index:  -1,
+	}, cost)
+	t.setExpiration(entry, expirationNs)
 	return
 }
 
+// full returns true if an entry with given cost does not fit in the cache.
+func (t *Tlru[K, V]) full(cost int64) bool {
+	return t.evictionList.Len() >= t.capacity || t.maxCost > 0 && t.evictionList.Cost()+cost > t.maxCost
+}
+
 // Get returns value of cached entry.
-func (t *Tlru) Get(key interface{}, trigger bool) (value interface{}, ok bool) {
-	if element, ok := t.elementMap[key]; ok {
+// Expired entry is removed instead of being returned.
+func (t *Tlru[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if entry, ok := t.evictionList.Get(key); ok {
+		if entry.Value.expired(t.clock.Now().UnixNano()) {
+			t.expire(entry)
+			return value, false
+		}
 		if trigger {
-			t.evictionList.MoveToFront(element)
+			t.evictionList.MoveToFront(entry)
 		}
-		return element.Value.(*entry).value, true
+		return entry.Value.value, true
 	}
-	return nil, false
+	return
 }
 
 // Remove removes cache entry.
-func (t *Tlru) Remove(key interface{}) bool {
-	element, ok := t.elementMap[key]
+func (t *Tlru[K, V]) Remove(key K) bool {
+	entry, ok := t.evictionList.Get(key)
 	if !ok {
 		return false
 	}
-	delete(t.elementMap, key)
-	t.evictionList.Remove(element)
+	t.evictionList.Remove(entry)
+	if entry.Value.index >= 0 {
+		heap.Remove(&t.expirationHeap, entry.Value.index)
+	}
+	return true
+}
+
+// Expire removes expired entries.
+// Entries are taken from the expiration heap in deadline order, so
+// entries which are not expired are never visited.
+// Returns count of removed entries.
+func (t *Tlru[K, V]) Expire() int {
+	count := 0
+	currentTimeNs := t.clock.Now().UnixNano()
+	for {
+		entry := t.expirationHeap.peek()
+		if entry == nil || !entry.Value.expired(currentTimeNs) {
+			return count
+		}
+		t.expire(entry)
+		count++
+	}
+}
+
+// setExpiration updates expiration of the entry and its position in the
+// expiration heap. Entries never expiring are kept out of the heap.
+func (t *Tlru[K, V]) setExpiration(entry *policyutil.Entry[K, item[V]], expirationNs int64) {
+	entry.Value.expirationNs = expirationNs
+	switch {
+	case expirationNs == 0 && entry.Value.index >= 0:
+		heap.Remove(&t.expirationHeap, entry.Value.index)
+	case expirationNs != 0 && entry.Value.index >= 0:
+		heap.Fix(&t.expirationHeap, entry.Value.index)
+	case expirationNs != 0:
+		heap.Push(&t.expirationHeap, entry)
+	}
+}
+
+// OnEvict sets handler called with entries evicted due to capacity.
+func (t *Tlru[K, V]) OnEvict(handler func(key K, value V)) {
+	t.evictHandler = handler
+}
+
+// OnExpire sets handler called with entries removed due to expiration.
+func (t *Tlru[K, V]) OnExpire(handler func(key K, value V)) {
+	t.expireHandler = handler
+}
+
+func (t *Tlru[K, V]) evict() bool {
+	entry := t.evictionList.Back()
+	if entry == nil {
+		return false
+	}
+	t.Remove(entry.Key)
+	if t.evictHandler != nil {
+		t.evictHandler(entry.Key, entry.Value.value)
+	}
 	return true
 }
 
-func (t *Tlru) evict() bool {
-	element := t.evictionList.Back()
-	if element != nil {
-		return t.Remove(element.Value.(*entry).key)
+func (t *Tlru[K, V]) expire(entry *policyutil.Entry[K, item[V]]) {
+	t.Remove(entry.Key)
+	if t.expireHandler != nil {
+		t.expireHandler(entry.Key, entry.Value.value)
 	}
-	return false
 }
 
 // Clear removes all entries in the cache.
-func (t *Tlru) Clear() int {
+func (t *Tlru[K, V]) Clear() int {
 	length := t.Len()
-	for key := range t.elementMap {
-		delete(t.elementMap, key)
-	}
-	t.evictionList.Init()
+	t.evictionList.Clear()
+	t.expirationHeap = make(expirationHeap[K, V], 0)
 	return length
 }
 
 // Len returns length of the cache.
-func (t *Tlru) Len() int {
+// Expired entries are removed before counting.
+func (t *Tlru[K, V]) Len() int {
+	t.Expire()
 	return t.evictionList.Len()
 }
 
 // Cap returns capacity of the cache.
-func (t *Tlru) Cap() int {
+func (t *Tlru[K, V]) Cap() int {
 	return t.capacity
 }
 
+// Cost returns total cost of the entries in the cache.
+// Expired entries are removed before counting.
+func (t *Tlru[K, V]) Cost() int64 {
+	t.Expire()
+	return t.evictionList.Cost()
+}
+
+// MaxCost returns max total cost of the cache, zero if unbounded.
+func (t *Tlru[K, V]) MaxCost() int64 {
+	return t.maxCost
+}
+
+// SetMaxCost set max total cost of the cache, zero for unbounded.
+// Entries are evicted until the total cost fits the new max cost.
+// Returns error if maxCost is negative value.
+func (t *Tlru[K, V]) SetMaxCost(maxCost int64) error {
+	if maxCost < 0 {
+		return errs.ErrNegativeMaxCost
+	}
+	t.maxCost = maxCost
+	if maxCost > 0 && t.evictionList.Cost() > maxCost {
+		t.Expire()
+	}
+	for maxCost > 0 && t.evictionList.Cost() > maxCost {
+		t.evict()
+	}
+	return nil
+}
+
+// SetWeigher sets function giving cost of the entries added by Add and
+// AddWithTTL. Costs of the entries already in the cache are not changed.
+func (t *Tlru[K, V]) SetWeigher(weigher func(key K, value V) int64) {
+	t.weigher = weigher
+}
+
+func (t *Tlru[K, V]) weigh(key K, value V) int64 {
+	if t.weigher == nil {
+		return 1
+	}
+	return t.weigher(key, value)
+}
+
 // SetCap set capacity of the cache.
+// Entries are evicted until the length fits the new capacity.
 // Returns error unless newCap is negative value.
-func (t *Tlru) SetCap(newCapacity int) error {
+func (t *Tlru[K, V]) SetCap(newCapacity int) error {
 	if newCapacity <= 0 {
-		return errors.New("capacity must be positive value")
+		return errs.ErrNonPositiveCapacity
 	}
-	if t.capacity < newCapacity {
-		diff := newCapacity - t.capacity
-		for i := 0; i < diff; i++ {
-			t.evict()
-		}
+	if t.evictionList.Len() > newCapacity {
+		t.Expire()
+	}
+	for t.evictionList.Len() > newCapacity {
+		t.evict()
 	}
 	t.capacity = newCapacity
 	return nil
 }
 
 // Keys returns a slice of entry keys in the cache.
-func (t *Tlru) Keys() []interface{} {
-	keys := make([]interface{}, 0, len(t.elementMap))
-	for k := range t.elementMap {
-		keys = append(keys, k)
-	}
-	return keys
+// Expired entries are removed before collecting.
+func (t *Tlru[K, V]) Keys() []K {
+	t.Expire()
+	return t.evictionList.Keys()
 }
 
 // Values returns a slice of entry values in the cache.
-func (t *Tlru) Values() []interface{} {
-	values := make([]interface{}, 0, len(t.elementMap))
-	for _, v := range t.elementMap {
-		values = append(values, v.Value.(*entry).value)
+// Expired entries are removed before collecting.
+func (t *Tlru[K, V]) Values() []V {
+	t.Expire()
+	values := make([]V, 0, t.evictionList.Len())
+	for entry := t.evictionList.Front(); entry != nil; entry = entry.Next() {
+		values = append(values, entry.Value.value)
 	}
 	return values
 }
 
+// Range calls fn for entries from the least recently added or used one to
+// the most recent one, until fn returns false.
+// Expired entries are removed before walking.
+func (t *Tlru[K, V]) Range(fn func(key K, value V) bool) {
+	t.Expire()
+	for entry := t.evictionList.Back(); entry != nil; entry = entry.Prev() {
+		if !fn(entry.Key, entry.Value.value) {
+			return
+		}
+	}
+}
+
+// Oldest returns the least recently added or used entry.
+// Expired entries are removed before taking it.
+// Returns false if the cache is empty.
+func (t *Tlru[K, V]) Oldest() (key K, value V, ok bool) {
+	t.Expire()
+	return entryOf[K, V](t.evictionList.Back())
+}
+
+// Newest returns the most recently added or used entry.
+// Expired entries are removed before taking it.
+// Returns false if the cache is empty.
+func (t *Tlru[K, V]) Newest() (key K, value V, ok bool) {
+	t.Expire()
+	return entryOf[K, V](t.evictionList.Front())
+}
+
+func entryOf[K comparable, V any](entry *policyutil.Entry[K, item[V]]) (key K, value V, ok bool) {
+	if entry == nil {
+		return
+	}
+	return entry.Key, entry.Value.value, true
+}
+
+// Age returns time passed since the entry was added or replaced.
+// Returns false if there is no such entry or it is expired.
+func (t *Tlru[K, V]) Age(key K) (age time.Duration, ok bool) {
+	entry, ok := t.evictionList.Get(key)
+	if !ok {
+		return 0, false
+	}
+	currentTimeNs := t.clock.Now().UnixNano()
+	if entry.Value.expired(currentTimeNs) {
+		return 0, false
+	}
+	return time.Duration(currentTimeNs - entry.Value.timeNs), true
+}
+
+// TTL returns remaining time to live of the entry, zero if it never expires.
+// Returns false if there is no such entry or it is expired.
+func (t *Tlru[K, V]) TTL(key K) (ttl time.Duration, ok bool) {
+	entry, ok := t.evictionList.Get(key)
+	if !ok {
+		return 0, false
+	}
+	if entry.Value.expirationNs == 0 {
+		return 0, true
+	}
+	currentTimeNs := t.clock.Now().UnixNano()
+	if entry.Value.expired(currentTimeNs) {
+		return 0, false
+	}
+	return time.Duration(entry.Value.expirationNs - currentTimeNs), true
+}
+
 // DaemonStarted returns true if expiration eviction daemon started
-func (t *Tlru) DaemonStarted() bool {
+func (t *Tlru[K, V]) DaemonStarted() bool {
+	t.daemonLock.Lock()
+	defer t.daemonLock.Unlock()
 	return t.daemonStarted
 }
 
+// SweepInterval returns interval of expiration daemon sweeps.
+// Defaults to the expiration duration.
+func (t *Tlru[K, V]) SweepInterval() time.Duration {
+	if t.sweepInterval > 0 {
+		return t.sweepInterval
+	}
+	return t.expirationDuration
+}
+
+// SetSweepInterval sets interval of expiration daemon sweeps.
+// It takes effect the next time the daemon is started.
+// Returns error unless interval is positive value.
+func (t *Tlru[K, V]) SetSweepInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: sweep interval must be positive value", errs.ErrInvalidTTL)
+	}
+	t.sweepInterval = interval
+	return nil
+}
+
 // ExpirationDuration returns duration of expiration
-func (t *Tlru) ExpirationDuration() time.Duration {
+func (t *Tlru[K, V]) ExpirationDuration() time.Duration {
 	return t.expirationDuration
 }
+
+func (i *item[V]) expired(currentTimeNs int64) bool {
+	return i.expirationNs != 0 && i.expirationNs <= currentTimeNs
+}
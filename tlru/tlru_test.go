@@ -1,13 +1,38 @@
 package tlru
 
 import (
+	"context"
+	"github.com/SemihBKGR/nucleus/nucleustest"
 	"math"
+	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 )
 
+func TestNew(t *testing.T) {
+	tlru, err := New[string, int](1, 0)
+	if tlru == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	tlru.Add("1", 1)
+	if value, ok := tlru.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	tlru, err = New[string, int](0, 0)
+	if tlru != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
 func TestTlru_Add(t *testing.T) {
 	capacity := 10
 	duration := time.Duration(0)
@@ -102,6 +127,39 @@ func TestTlru_StartDaemon2(t *testing.T) {
 	lock.Unlock()
 }
 
+func TestTlru_AddWithTTL(t *testing.T) {
+	capacity := 10
+	duration := 50 * time.Millisecond
+	tlru, _ := NewTlru(capacity, duration)
+	tlru.AddWithTTL(1, nil, 10*time.Millisecond)
+	tlru.AddWithTTL(2, nil, time.Second)
+	tlru.Add(3, nil)
+	tlru.AddWithTTL(4, nil, 0)
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := tlru.Get(1, true); ok {
+		t.FailNow()
+	}
+	for _, key := range []int{2, 3, 4} {
+		if _, ok := tlru.Get(key, true); !ok {
+			t.FailNow()
+		}
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := tlru.Get(3, true); ok {
+		t.FailNow()
+	}
+	for _, key := range []int{2, 4} {
+		if _, ok := tlru.Get(key, true); !ok {
+			t.FailNow()
+		}
+	}
+	// adding again resets the expiration
+	tlru.AddWithTTL(1, nil, time.Second)
+	if _, ok := tlru.Get(1, true); !ok {
+		t.FailNow()
+	}
+}
+
 func TestTlru_Get(t *testing.T) {
 	capacity := 10
 	duration := time.Duration(0)
@@ -276,3 +334,470 @@ func containsAll(s []interface{}, es ...interface{}) bool {
 	}
 	return true
 }
+
+func TestTlru_Expire(t *testing.T) {
+	capacity := 10
+	tlru, _ := NewTlru(capacity, 10*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		tlru.Add(i, nil)
+	}
+	tlru.AddWithTTL(5, nil, time.Second)
+	time.Sleep(20 * time.Millisecond)
+	if tlru.evictionList.Len() != 6 {
+		t.FailNow()
+	}
+	if tlru.Len() != 1 || len(tlru.Keys()) != 1 || len(tlru.Values()) != 1 {
+		t.FailNow()
+	}
+	if tlru.Expire() != 0 {
+		t.FailNow()
+	}
+	tlru.AddWithTTL(6, nil, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := tlru.Get(6, false); ok {
+		t.FailNow()
+	}
+	if _, ok := tlru.evictionList.Get(6); ok {
+		t.FailNow()
+	}
+}
+
+func TestTlru_StopDaemon(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	capacity := 10
+	duration := 10 * time.Millisecond
+	tlru, _ := NewTlru(capacity, duration)
+	if tlru.StopDaemon() {
+		t.FailNow()
+	}
+	lock := sync.RWMutex{}
+	tlru.StartDaemon(&lock)
+	if !tlru.StopDaemon() || tlru.DaemonStarted() {
+		t.FailNow()
+	}
+	if !waitGoroutines(goroutines) {
+		t.FailNow()
+	}
+	// stopped daemon can be started again
+	if !tlru.StartDaemon(&lock) || !tlru.StopDaemon() {
+		t.FailNow()
+	}
+	if !waitGoroutines(goroutines) {
+		t.FailNow()
+	}
+}
+
+func TestTlru_StartDaemonContext(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	capacity := 10
+	duration := 10 * time.Millisecond
+	tlru, _ := NewTlru(capacity, duration)
+	ctx, cancel := context.WithCancel(context.Background())
+	lock := sync.Mutex{}
+	if !tlru.StartDaemonContext(ctx, &lock) {
+		t.FailNow()
+	}
+	lock.Lock()
+	tlru.Add(1, nil)
+	lock.Unlock()
+	time.Sleep(30 * time.Millisecond)
+	lock.Lock()
+	if tlru.evictionList.Len() != 0 {
+		t.FailNow()
+	}
+	lock.Unlock()
+	cancel()
+	if !waitGoroutines(goroutines) {
+		t.FailNow()
+	}
+	// daemon exited as its ctx is done can be started again
+	if tlru.DaemonStarted() || tlru.StopDaemon() {
+		t.FailNow()
+	}
+	if !tlru.StartDaemonContext(context.Background(), &lock) || !tlru.DaemonStarted() {
+		t.FailNow()
+	}
+	lock.Lock()
+	tlru.Add(2, nil)
+	lock.Unlock()
+	time.Sleep(30 * time.Millisecond)
+	lock.Lock()
+	if tlru.evictionList.Len() != 0 {
+		t.FailNow()
+	}
+	lock.Unlock()
+	if !tlru.StopDaemon() || !waitGoroutines(goroutines) {
+		t.FailNow()
+	}
+}
+
+func TestTlru_SetSweepInterval(t *testing.T) {
+	capacity := 10
+	tlru, _ := NewTlru(capacity, 0)
+	if tlru.SweepInterval() != 0 {
+		t.FailNow()
+	}
+	if tlru.StartDaemon(&sync.RWMutex{}) {
+		t.FailNow()
+	}
+	if tlru.SetSweepInterval(0) == nil {
+		t.FailNow()
+	}
+	if tlru.SetSweepInterval(10*time.Millisecond) != nil || tlru.SweepInterval() != 10*time.Millisecond {
+		t.FailNow()
+	}
+	lock := sync.RWMutex{}
+	if !tlru.StartDaemon(&lock) {
+		t.FailNow()
+	}
+	defer tlru.StopDaemon()
+	lock.Lock()
+	tlru.AddWithTTL(1, nil, 10*time.Millisecond)
+	tlru.Add(2, nil)
+	lock.Unlock()
+	time.Sleep(30 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	if _, ok := tlru.evictionList.Get(1); ok {
+		t.FailNow()
+	}
+	if _, ok := tlru.evictionList.Get(2); !ok {
+		t.FailNow()
+	}
+}
+
+func waitGoroutines(n int) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= n {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestTlru_Expire2(t *testing.T) {
+	capacity := 100
+	tlru, _ := NewTlru(capacity, 0)
+	for i := 0; i < capacity; i++ {
+		tlru.AddWithTTL(i, nil, time.Duration(capacity-i)*time.Millisecond)
+	}
+	// updating moves the entries in the heap
+	tlru.AddWithTTL(99, nil, time.Hour)
+	tlru.AddWithTTL(98, nil, 0)
+	tlru.Remove(97)
+	if tlru.expirationHeap.Len() != capacity-2 {
+		t.FailNow()
+	}
+	for i, entry := range tlru.expirationHeap {
+		if entry.Value.index != i {
+			t.FailNow()
+		}
+	}
+	time.Sleep(time.Duration(capacity+10) * time.Millisecond)
+	if tlru.Expire() != capacity-3 {
+		t.FailNow()
+	}
+	if !containsAll(tlru.Keys(), 98, 99) || tlru.Len() != 2 {
+		t.FailNow()
+	}
+	if tlru.expirationHeap.Len() != 1 || tlru.expirationHeap.peek().Key != 99 {
+		t.FailNow()
+	}
+	tlru.Clear()
+	if tlru.expirationHeap.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestTlru_OnEvict(t *testing.T) {
+	capacity := 10
+	tlru, _ := NewTlru(capacity, 0)
+	evicted := make([]interface{}, 0)
+	tlru.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		tlru.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	tlru.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || tlru.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != 0 {
+		t.FailNow()
+	}
+	if _, ok := tlru.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	tlru.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || tlru.Len() != capacity/2 {
+		t.FailNow()
+	}
+}
+
+func TestTlru_AddWithCost(t *testing.T) {
+	capacity := 10
+	tlru, _ := NewTlru(capacity, 0)
+	_ = tlru.SetMaxCost(10)
+	tlru.AddWithCost(1, "1", 4)
+	tlru.AddWithCost(2, "2", 4)
+	if tlru.Cost() != 8 || tlru.MaxCost() != 10 {
+		t.FailNow()
+	}
+	// 3 does not fit until an entry is evicted
+	if !tlru.AddWithCost(3, "3", 4) || tlru.Cost() != 8 || tlru.Len() != 2 {
+		t.FailNow()
+	}
+	if _, ok := tlru.Get(1, false); ok {
+		t.FailNow()
+	}
+	// updating replaces the cost
+	if tlru.AddWithCost(3, "3", 1) || tlru.Cost() != 5 {
+		t.FailNow()
+	}
+	// entries costlier than max cost are evicted right away
+	evicted := make([]interface{}, 0)
+	tlru.OnEvict(func(key, value interface{}) {
+		evicted = append(evicted, key)
+	})
+	if !tlru.AddWithCost(4, "4", 11) || tlru.Cost() != 5 || len(evicted) != 1 || evicted[0] != 4 {
+		t.FailNow()
+	}
+	if _, ok := tlru.Get(4, false); ok {
+		t.FailNow()
+	}
+}
+
+func TestTlru_SetMaxCost(t *testing.T) {
+	capacity := 10
+	tlru, _ := NewTlru(capacity, 0)
+	tlru.SetWeigher(func(key, value interface{}) int64 {
+		return int64(len(value.(string)))
+	})
+	for i := 0; i < capacity; i++ {
+		tlru.Add(i, strings.Repeat("a", i))
+	}
+	if tlru.Cost() != 45 || tlru.MaxCost() != 0 {
+		t.FailNow()
+	}
+	if tlru.SetMaxCost(-1) == nil {
+		t.FailNow()
+	}
+	if tlru.SetMaxCost(20) != nil || tlru.Cost() > 20 || tlru.MaxCost() != 20 {
+		t.FailNow()
+	}
+	tlru.Clear()
+	if tlru.Cost() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestTlru_Range(t *testing.T) {
+	capacity := 5
+	tlru, _ := NewTlru(capacity, time.Hour)
+	for i := 0; i < capacity; i++ {
+		tlru.Add(i, i)
+	}
+	tlru.Get(0, true)
+	keys := make([]interface{}, 0)
+	tlru.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+	if len(keys) != 3 {
+		t.FailNow()
+	}
+	for i, key := range keys {
+		if key != i+1 {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTlru_TTL(t *testing.T) {
+	capacity := 5
+	tlru, _ := NewTlru(capacity, time.Hour)
+	tlru.Add(1, 1)
+	tlru.AddWithTTL(2, 2, 0)
+	tlru.AddWithTTL(3, 3, time.Millisecond)
+	if ttl, ok := tlru.TTL(1); !ok || ttl <= 59*time.Minute || ttl > time.Hour {
+		t.FailNow()
+	}
+	if ttl, ok := tlru.TTL(2); !ok || ttl != 0 {
+		t.FailNow()
+	}
+	time.Sleep(2 * time.Millisecond)
+	if _, ok := tlru.TTL(3); ok {
+		t.FailNow()
+	}
+	if _, ok := tlru.TTL(4); ok {
+		t.FailNow()
+	}
+}
+
+func TestTlru_Oldest(t *testing.T) {
+	capacity := 5
+	tlru, _ := NewTlru(capacity, time.Hour)
+	if _, _, ok := tlru.Oldest(); ok {
+		t.FailNow()
+	}
+	for i := 0; i < capacity; i++ {
+		tlru.Add(i, i)
+	}
+	tlru.Get(0, true)
+	if key, value, ok := tlru.Oldest(); !ok || key != 1 || value != 1 {
+		t.FailNow()
+	}
+	if key, value, ok := tlru.Newest(); !ok || key != 0 || value != 0 {
+		t.FailNow()
+	}
+}
+
+func TestTlru_Age(t *testing.T) {
+	capacity := 5
+	tlru, _ := NewTlru(capacity, time.Hour)
+	tlru.Add(1, 1)
+	tlru.AddWithTTL(2, 2, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if age, ok := tlru.Age(1); !ok || age < 5*time.Millisecond {
+		t.FailNow()
+	}
+	if _, ok := tlru.Age(2); ok {
+		t.FailNow()
+	}
+	tlru.Get(1, true)
+	if age, _ := tlru.Age(1); age < 5*time.Millisecond {
+		t.FailNow()
+	}
+	tlru.Add(1, 1)
+	if age, _ := tlru.Age(1); age >= 5*time.Millisecond {
+		t.FailNow()
+	}
+}
+
+func TestTlru_SetClock(t *testing.T) {
+	capacity := 5
+	clock := nucleustest.NewFakeClock(time.Now())
+	tlru, _ := NewTlru(capacity, time.Minute)
+	tlru.SetClock(clock)
+	tlru.Add(1, 1)
+	tlru.AddWithTTL(2, 2, time.Hour)
+	clock.Advance(59 * time.Second)
+	if _, ok := tlru.Get(1, true); !ok {
+		t.FailNow()
+	}
+	if ttl, _ := tlru.TTL(2); ttl != time.Hour-59*time.Second {
+		t.FailNow()
+	}
+	clock.Advance(time.Second)
+	if _, ok := tlru.Get(1, true); ok || tlru.Len() != 1 {
+		t.FailNow()
+	}
+	tlru.SetClock(nil)
+	if _, ok := tlru.clock.(systemClock); !ok {
+		t.FailNow()
+	}
+}
+
+func TestTlru_SetClock2(t *testing.T) {
+	capacity := 5
+	clock := nucleustest.NewFakeClock(time.Now())
+	tlru, _ := NewTlru(capacity, time.Minute)
+	tlru.SetClock(clock)
+	lock := sync.Mutex{}
+	expired := 0
+	tlru.OnExpire(func(key, value interface{}) {
+		expired++
+	})
+	tlru.Add(1, 1)
+	tlru.AddWithTTL(2, 2, 2*time.Minute)
+	tlru.StartDaemonContext(context.Background(), &lock)
+	defer tlru.StopDaemon()
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	// the daemon waits for the next sweep once it is done with this one
+	clock.BlockUntil(1)
+	lock.Lock()
+	if expired != 1 || tlru.evictionList.Len() != 1 {
+		t.FailNow()
+	}
+	lock.Unlock()
+	clock.Advance(time.Minute)
+	clock.BlockUntil(1)
+	lock.Lock()
+	if expired != 2 || tlru.evictionList.Len() != 0 {
+		t.FailNow()
+	}
+	lock.Unlock()
+}
+
+func TestTlru_AddWithTTL3(t *testing.T) {
+	capacity := 5
+	clock := nucleustest.NewFakeClock(time.Now())
+	tlru, _ := NewTlru(capacity, 0)
+	tlru.SetClock(clock)
+	// ttl is not truncated to milliseconds
+	tlru.AddWithTTL(1, 1, 500*time.Microsecond)
+	tlru.AddWithTTL(2, 2, 1500*time.Microsecond)
+	if _, ok := tlru.Get(1, true); !ok {
+		t.FailNow()
+	}
+	if ttl, _ := tlru.TTL(1); ttl != 500*time.Microsecond {
+		t.FailNow()
+	}
+	clock.Advance(499 * time.Microsecond)
+	if _, ok := tlru.Get(1, true); !ok {
+		t.FailNow()
+	}
+	clock.Advance(time.Microsecond)
+	if _, ok := tlru.Get(1, true); ok {
+		t.FailNow()
+	}
+	clock.Advance(999 * time.Microsecond)
+	if _, ok := tlru.Get(2, true); !ok {
+		t.FailNow()
+	}
+	clock.Advance(time.Microsecond)
+	if _, ok := tlru.Get(2, true); ok {
+		t.FailNow()
+	}
+}
+
+func TestTlru_Add3(t *testing.T) {
+	capacity := 5
+	clock := nucleustest.NewFakeClock(time.Now())
+	tlru, _ := NewTlru(capacity, time.Minute)
+	tlru.SetClock(clock)
+	// updated entries keep their ttl which restarts
+	tlru.AddWithTTL(1, 1, time.Hour)
+	tlru.AddWithTTL(2, 2, 0)
+	clock.Advance(30 * time.Minute)
+	tlru.Add(1, 10)
+	tlru.Add(2, 20)
+	if ttl, _ := tlru.TTL(1); ttl != time.Hour {
+		t.FailNow()
+	}
+	clock.Advance(59 * time.Minute)
+	if value, ok := tlru.Get(1, true); !ok || value != 10 {
+		t.FailNow()
+	}
+	if value, ok := tlru.Get(2, true); !ok || value != 20 {
+		t.FailNow()
+	}
+	clock.Advance(time.Minute)
+	if _, ok := tlru.Get(1, true); ok {
+		t.FailNow()
+	}
+	tlru.Add(1, 1)
+	if ttl, _ := tlru.TTL(1); ttl != time.Minute {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,234 @@
+package twoq
+
+import (
+	"container/list"
+	"fmt"
+	"github.com/SemihBKGR/nucleus/internal/errs"
+)
+
+const (
+	// DefaultRecentRatio is the share of the capacity commonly used for the recent fifo queue.
+	DefaultRecentRatio = 0.25
+	// DefaultGhostRatio is the share of the capacity commonly used for remembered evicted keys.
+	DefaultGhostRatio = 0.5
+)
+
+// TwoQ Two queue cache policy.
+// New entries are added into the recent fifo queue, keys evicted from it are
+// remembered in the ghost queue, and only keys seen again while remembered
+// are admitted into the frequent lru queue.
+type TwoQ[K comparable, V any] struct {
+	capacity     int
+	recentRatio  float64
+	ghostRatio   float64
+	recentCap    int
+	ghostCap     int
+	elementMap   map[K]*list.Element
+	recent       *list.List
+	frequent     *list.List
+	ghost        *list.List
+	evictHandler func(key K, value V)
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	owner *list.List
+}
+
+// New returns new typed twoq
+func New[K comparable, V any](capacity int, recentRatio, ghostRatio float64) (*TwoQ[K, V], error) {
+	if capacity <= 0 {
+		return nil, errs.ErrNonPositiveCapacity
+	}
+	if recentRatio <= 0 || recentRatio >= 1 {
+		return nil, fmt.Errorf("%w: recent ratio must be between 0 and 1", errs.ErrInvalidArgument)
+	}
+	if ghostRatio < 0 {
+		return nil, fmt.Errorf("%w: ghost ratio must not be negative value", errs.ErrInvalidArgument)
+	}
+	twoQ := &TwoQ[K, V]{
+		recentRatio: recentRatio,
+		ghostRatio:  ghostRatio,
+		elementMap:  make(map[K]*list.Element),
+		recent:      list.New(),
+		frequent:    list.New(),
+		ghost:       list.New(),
+	}
+	twoQ.resize(capacity)
+	return twoQ, nil
+}
+
+// NewTwoQ returns new twoq
+func NewTwoQ(capacity int, recentRatio, ghostRatio float64) (*TwoQ[interface{}, interface{}], error) {
+	return New[interface{}, interface{}](capacity, recentRatio, ghostRatio)
+}
+
+// Add adds entry in cache
+func (t *TwoQ[K, V]) Add(key K, value V) (eviction bool) {
+	element, ok := t.elementMap[key]
+	if ok && element.Value.(*entry[K, V]).owner != t.ghost {
+		entry := element.Value.(*entry[K, V])
+		entry.value = value
+		if entry.owner == t.frequent {
+			t.frequent.MoveToFront(element)
+		}
+		return false
+	}
+	eviction = t.Len() >= t.capacity
+	if eviction {
+		t.evict()
+	}
+	if ok {
+		t.ghost.Remove(element)
+		entry := element.Value.(*entry[K, V])
+		entry.value = value
+		entry.owner = t.frequent
+		t.elementMap[key] = t.frequent.PushFront(entry)
+		return
+	}
+	entry := &entry[K, V]{
+		key:   key,
+		value: value,
+		owner: t.recent,
+	}
+	t.elementMap[key] = t.recent.PushFront(entry)
+	return
+}
+
+// Get returns value of cached entry.
+// Accesses to entries in the recent queue do not change their position,
+// since they are usually correlated with the access that added them.
+func (t *TwoQ[K, V]) Get(key K, trigger bool) (value V, ok bool) {
+	if element, ok := t.elementMap[key]; ok {
+		entry := element.Value.(*entry[K, V])
+		if entry.owner == t.ghost {
+			return value, false
+		}
+		if trigger && entry.owner == t.frequent {
+			t.frequent.MoveToFront(element)
+		}
+		return entry.value, true
+	}
+	return
+}
+
+// Remove removes cache entry.
+func (t *TwoQ[K, V]) Remove(key K) bool {
+	element, ok := t.elementMap[key]
+	if !ok {
+		return false
+	}
+	entry := element.Value.(*entry[K, V])
+	delete(t.elementMap, key)
+	entry.owner.Remove(element)
+	return entry.owner != t.ghost
+}
+
+// OnEvict sets handler called with entries evicted due to capacity.
+func (t *TwoQ[K, V]) OnEvict(handler func(key K, value V)) {
+	t.evictHandler = handler
+}
+
+// evict removes an entry from the recent queue when it exceeds its capacity,
+// remembering its key in the ghost queue, or from the frequent queue otherwise.
+func (t *TwoQ[K, V]) evict() bool {
+	if element := t.recent.Back(); element != nil && (t.recent.Len() > t.recentCap || t.frequent.Len() == 0) {
+		evicted := element.Value.(*entry[K, V])
+		t.recent.Remove(element)
+		if t.evictHandler != nil {
+			t.evictHandler(evicted.key, evicted.value)
+		}
+		if t.ghostCap == 0 {
+			delete(t.elementMap, evicted.key)
+			return true
+		}
+		var zero V
+		evicted.value = zero
+		evicted.owner = t.ghost
+		t.elementMap[evicted.key] = t.ghost.PushFront(evicted)
+		t.trimGhost()
+		return true
+	}
+	if element := t.frequent.Back(); element != nil {
+		evicted := element.Value.(*entry[K, V])
+		t.Remove(evicted.key)
+		if t.evictHandler != nil {
+			t.evictHandler(evicted.key, evicted.value)
+		}
+		return true
+	}
+	return false
+}
+
+func (t *TwoQ[K, V]) resize(capacity int) {
+	t.capacity = capacity
+	t.recentCap = max(1, int(float64(capacity)*t.recentRatio))
+	t.ghostCap = int(float64(capacity) * t.ghostRatio)
+	for t.Len() > capacity {
+		t.evict()
+	}
+	t.trimGhost()
+}
+
+func (t *TwoQ[K, V]) trimGhost() {
+	for t.ghost.Len() > t.ghostCap {
+		ghost := t.ghost.Back()
+		delete(t.elementMap, ghost.Value.(*entry[K, V]).key)
+		t.ghost.Remove(ghost)
+	}
+}
+
+// Clear removes all entries in the cache.
+func (t *TwoQ[K, V]) Clear() int {
+	length := t.Len()
+	for key := range t.elementMap {
+		delete(t.elementMap, key)
+	}
+	t.recent.Init()
+	t.frequent.Init()
+	t.ghost.Init()
+	return length
+}
+
+// Len returns length of the cache.
+func (t *TwoQ[K, V]) Len() int {
+	return t.recent.Len() + t.frequent.Len()
+}
+
+// Cap returns capacity of the cache.
+func (t *TwoQ[K, V]) Cap() int {
+	return t.capacity
+}
+
+// SetCap set capacity of the cache.
+// Returns error unless newCap is negative value.
+func (t *TwoQ[K, V]) SetCap(newCapacity int) error {
+	if newCapacity <= 0 {
+		return errs.ErrNonPositiveCapacity
+	}
+	t.resize(newCapacity)
+	return nil
+}
+
+// Keys returns a slice of entry keys in the cache.
+func (t *TwoQ[K, V]) Keys() []K {
+	keys := make([]K, 0, t.Len())
+	for k, v := range t.elementMap {
+		if v.Value.(*entry[K, V]).owner != t.ghost {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Values returns a slice of entry values in the cache.
+func (t *TwoQ[K, V]) Values() []V {
+	values := make([]V, 0, t.Len())
+	for _, v := range t.elementMap {
+		if entry := v.Value.(*entry[K, V]); entry.owner != t.ghost {
+			values = append(values, entry.value)
+		}
+	}
+	return values
+}
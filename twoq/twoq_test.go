@@ -0,0 +1,314 @@
+package twoq
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNewTwoQ(t *testing.T) {
+	twoQ, err := NewTwoQ(1, DefaultRecentRatio, DefaultGhostRatio)
+	if twoQ == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	twoQ, err = NewTwoQ(0, DefaultRecentRatio, DefaultGhostRatio)
+	if twoQ != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	twoQ, err = NewTwoQ(-1, DefaultRecentRatio, DefaultGhostRatio)
+	if twoQ != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	twoQ, err = NewTwoQ(1, 0, DefaultGhostRatio)
+	if twoQ != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+	twoQ, err = NewTwoQ(1, DefaultRecentRatio, -1)
+	if twoQ != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNew(t *testing.T) {
+	twoQ, err := New[string, int](1, DefaultRecentRatio, DefaultGhostRatio)
+	if twoQ == nil {
+		t.FailNow()
+	}
+	if err != nil {
+		t.FailNow()
+	}
+	twoQ.Add("1", 1)
+	if value, ok := twoQ.Get("1", true); !ok || value != 1 {
+		t.FailNow()
+	}
+	twoQ, err = New[string, int](0, DefaultRecentRatio, DefaultGhostRatio)
+	if twoQ != nil {
+		t.FailNow()
+	}
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestTwoQ_Add(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	for i := 0; i < capacity*2; i++ {
+		twoQ.Add(i, strconv.Itoa(i))
+	}
+}
+
+func TestTwoQ_Add2(t *testing.T) {
+	capacity := 3
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	// 1 -> r:[] f:[] g:[]
+	twoQ.Add(1, nil)
+	// r:[1] f:[] g:[]
+	if !contains(twoQ.Keys(), 1) {
+		t.FailNow()
+	}
+	// 2 -> r:[1] f:[] g:[]
+	twoQ.Add(2, nil)
+	// r:[1,2] f:[] g:[]
+	if !containsAll(twoQ.Keys(), 1, 2) {
+		t.FailNow()
+	}
+	// 3 -> r:[1,2] f:[] g:[]
+	twoQ.Add(3, nil)
+	// r:[1,2,3] f:[] g:[]
+	if !containsAll(twoQ.Keys(), 1, 2, 3) {
+		t.FailNow()
+	}
+	// 4 -> r:[1,2,3] f:[] g:[]
+	twoQ.Add(4, nil)
+	// r:[2,3,4] f:[] g:[1]
+	if !containsAll(twoQ.Keys(), 2, 3, 4) {
+		t.FailNow()
+	}
+	// 1 -> r:[2,3,4] f:[] g:[1]
+	twoQ.Add(1, nil)
+	// r:[3,4] f:[1] g:[2]
+	if !containsAll(twoQ.Keys(), 1, 3, 4) || twoQ.frequent.Len() != 1 {
+		t.FailNow()
+	}
+	// 5 -> r:[3,4] f:[1] g:[2]
+	twoQ.Add(5, nil)
+	// r:[4,5] f:[1] g:[3]
+	if !containsAll(twoQ.Keys(), 1, 4, 5) {
+		t.FailNow()
+	}
+	if _, ok := twoQ.Get(3, true); ok {
+		t.FailNow()
+	}
+}
+
+func TestTwoQ_Get(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	for i := 0; i < capacity; i++ {
+		twoQ.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		value, ok := twoQ.Get(i, true)
+		if !ok || value.(string) != strconv.Itoa(i) {
+			t.FailNow()
+		}
+	}
+	for i := capacity; i < capacity*2; i++ {
+		value, ok := twoQ.Get(i, true)
+		if ok || value != nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTwoQ_Remove(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	for i := 0; i < capacity; i++ {
+		twoQ.Add(i, strconv.Itoa(i))
+	}
+	for i := 0; i < capacity; i++ {
+		ok := twoQ.Remove(i)
+		if !ok {
+			t.FailNow()
+		}
+	}
+	for i := 0; i < capacity; i++ {
+		ok := twoQ.Remove(i)
+		if ok {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTwoQ_Clear(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	for i := 0; i < capacity; i++ {
+		twoQ.Add(i, strconv.Itoa(i))
+	}
+	twoQ.Clear()
+	if twoQ.Len() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestTwoQ_Cap(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	if twoQ.Cap() != capacity {
+		t.FailNow()
+	}
+}
+
+func TestTwoQ_Len(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	if twoQ.Len() != 0 {
+		t.FailNow()
+	}
+	for i := 0; i < capacity*2; i++ {
+		twoQ.Add(i, strconv.Itoa(i))
+		if twoQ.Len() != int(math.Min(float64(i+1), float64(capacity))) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTwoQ_SetCap(t *testing.T) {
+	capacity := 10
+	newCapacity := 20
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	err := twoQ.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if twoQ.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = 5
+	err = twoQ.SetCap(newCapacity)
+	if err != nil {
+		t.FailNow()
+	}
+	if twoQ.Cap() != newCapacity {
+		t.FailNow()
+	}
+	newCapacity = -1
+	err = twoQ.SetCap(newCapacity)
+	if err == nil {
+		t.FailNow()
+	}
+	if twoQ.Cap() == newCapacity {
+		t.FailNow()
+	}
+}
+
+func TestTwoQ_Keys(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	for i := 0; i < capacity*2; i++ {
+		twoQ.Add(i, strconv.Itoa(i))
+		keys := twoQ.Keys()
+		if !contains(keys, i) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTwoQ_Values(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	for i := 0; i < capacity*2; i++ {
+		twoQ.Add(i, strconv.Itoa(i))
+		values := twoQ.Values()
+		if !contains(values, strconv.Itoa(i)) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestTwoQ_Scan(t *testing.T) {
+	capacity := 10
+	twoQ, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	for i := 0; i < capacity+capacity/2; i++ {
+		twoQ.Add(i, nil)
+	}
+	for i := 0; i < capacity/2; i++ {
+		twoQ.Add(i, nil)
+	}
+	for i := capacity * 2; i < capacity*10; i++ {
+		twoQ.Add(i, nil)
+	}
+	for i := 0; i < capacity/2; i++ {
+		if _, ok := twoQ.Get(i, false); !ok {
+			t.FailNow()
+		}
+	}
+}
+
+func contains(s []interface{}, e interface{}) bool {
+	for _, c := range s {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(s []interface{}, es ...interface{}) bool {
+	for _, e := range es {
+		if !contains(s, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTwoQ_OnEvict(t *testing.T) {
+	capacity := 10
+	twoq, _ := NewTwoQ(capacity, DefaultRecentRatio, DefaultGhostRatio)
+	evicted := make([]interface{}, 0)
+	twoq.OnEvict(func(key, value interface{}) {
+		if value != strconv.Itoa(key.(int)) {
+			t.Error("unexpected evicted value")
+		}
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < capacity; i++ {
+		twoq.Add(i, strconv.Itoa(i))
+	}
+	if len(evicted) != 0 {
+		t.FailNow()
+	}
+	twoq.Add(capacity, strconv.Itoa(capacity))
+	if len(evicted) != 1 || twoq.Len() != capacity {
+		t.FailNow()
+	}
+	if evicted[0] != 0 {
+		t.FailNow()
+	}
+	if _, ok := twoq.Get(evicted[0], false); ok {
+		t.FailNow()
+	}
+	twoq.SetCap(capacity / 2)
+	if len(evicted) != 1+capacity/2 || twoq.Len() != capacity/2 {
+		t.FailNow()
+	}
+}